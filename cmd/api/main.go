@@ -4,6 +4,14 @@
 // @BasePath /api/v1
 // @schemes http
 // @host localhost:8080
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token: "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key of a machine client
 package main
 
 import (
@@ -19,9 +27,17 @@ import (
 	"subscription_service/internal/config"
 	"subscription_service/internal/httpapi"
 	subscriptionHandler "subscription_service/internal/httpapi"
+	apiKeyRepo "subscription_service/internal/repository/apikey"
+	exchangeRateRepo "subscription_service/internal/repository/exchangerate"
+	idempotencyRepo "subscription_service/internal/repository/idempotency"
 	subscriptionRepo "subscription_service/internal/repository/subscription"
 	"subscription_service/internal/server"
+	apiKeyService "subscription_service/internal/service/apikey"
+	exchangeRateService "subscription_service/internal/service/exchangerate"
+	idempotencyService "subscription_service/internal/service/idempotency"
 	subscriptionService "subscription_service/internal/service/subscription"
+	"subscription_service/internal/worker"
+	"subscription_service/pkg/jwt"
 	"subscription_service/pkg/logger"
 	"subscription_service/pkg/postgres"
 )
@@ -37,7 +53,19 @@ func main() {
 	// 2. Init logger
 	log := logger.New(cfg.Server.LogLevel)
 
-	// 3. Init db
+	// 3. Init token verification
+	keys, err := loadSigningKeys(cfg.Auth)
+	if err != nil {
+		log.Error("load signing keys", "error", err)
+		os.Exit(1)
+	}
+	verifier := jwt.NewVerifier(keys, jwt.Expectations{
+		Issuer:   cfg.Auth.Issuer,
+		Audience: cfg.Auth.Audience,
+		Leeway:   cfg.Auth.Leeway,
+	})
+
+	// 4. Init db
 	db, err := postgres.NewConnection(context.Background(), cfg.Database.DSN())
 	if err != nil {
 		log.Error("open database", "error", err)
@@ -45,23 +73,39 @@ func main() {
 	}
 	defer db.Close()
 
-	// 4. Init deps (repository, service, HTTP handlers)
+	// 5. Init deps (repository, service, HTTP handlers)
 	repo := subscriptionRepo.New(db)
 	service := subscriptionService.New(repo)
 	handler := subscriptionHandler.NewSubscriptionHandler(log, service)
+	ratesHandler := subscriptionHandler.NewExchangeRateHandler(log, exchangeRateService.New(exchangeRateRepo.New(db)))
+	idempotency := idempotencyService.New(idempotencyRepo.New(db), cfg.Idempotency.TTL)
+	idempotencyMiddleware := subscriptionHandler.NewIdempotencyMiddleware(log, idempotency)
+	authMiddleware := subscriptionHandler.NewAuthMiddleware(log, verifier)
+	apiKeys := apiKeyService.New(apiKeyRepo.New(db))
+	keysHandler := subscriptionHandler.NewAPIKeyHandler(log, apiKeys)
+	apiKeyMiddleware := subscriptionHandler.NewAPIKeyMiddleware(log, apiKeys)
 
-	// 5. Init HTTP router and server
-	router := httpapi.NewHandler(log.With("component", "http"), handler)
+	// Purge expired trash and idempotency keys in the background until shutdown
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go worker.NewTrashPurger(log.With("component", "trash"), service, cfg.Trash).Run(purgeCtx)
+	go worker.NewIdempotencyPurger(log.With("component", "idempotency"), idempotency, cfg.Idempotency).Run(purgeCtx)
+
+	// 6. Init HTTP router and server
+	router := httpapi.NewHandler(
+		log.With("component", "http"), handler, ratesHandler, keysHandler,
+		idempotencyMiddleware, authMiddleware, apiKeyMiddleware,
+	)
 	srv := server.New(cfg.Server, router)
 
 	errCh := make(chan error, 1)
 	go func() {
 		log.Info("http server started", "addr", srv.Addr)
-		// 6. Run HTTP server
+		// 7. Run HTTP server
 		errCh <- srv.ListenAndServe()
 	}()
 
-	// 7. Listen shutdown signals
+	// 8. Listen shutdown signals
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -75,6 +119,8 @@ func main() {
 		}
 	}
 
+	stopPurge()
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
@@ -85,3 +131,20 @@ func main() {
 
 	log.Info("server stopped")
 }
+
+// loadSigningKeys reads the token verification keys from the configured key file or JWKS file.
+func loadSigningKeys(cfg config.AuthConfig) ([]jwt.Key, error) {
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+		return jwt.ParseJWKS(data)
+	}
+
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	return jwt.ParseKeyFile(data)
+}
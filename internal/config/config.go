@@ -11,8 +11,11 @@ import (
 )
 
 type Config struct {
-	Server   HTTPServer
-	Database DatabaseConfig
+	Server      HTTPServer
+	Database    DatabaseConfig
+	Trash       TrashConfig
+	Idempotency IdempotencyConfig
+	Auth        AuthConfig
 }
 
 type HTTPServer struct {
@@ -24,6 +27,38 @@ type HTTPServer struct {
 	ShutdownTimeout time.Duration
 }
 
+// TrashConfig controls how long deleted subscriptions are kept before they are purged.
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// IdempotencyConfig controls how long Idempotency-Key responses are kept for replay.
+type IdempotencyConfig struct {
+	TTL           time.Duration
+	PurgeInterval time.Duration
+}
+
+// AuthConfig configures JWT bearer authentication. Tokens are verified with the keys in exactly
+// one of KeyFile (PEM public keys or an HMAC secret) and JWKSFile (a JSON Web Key Set), and must
+// be issued by Issuer for Audience.
+type AuthConfig struct {
+	KeyFile  string
+	JWKSFile string
+	Issuer   string
+	Audience string
+	// Leeway allows for clock skew when checking token expiry.
+	Leeway time.Duration
+}
+
+const (
+	defaultTrashRetention           = 30 * 24 * time.Hour
+	defaultTrashPurgeInterval       = time.Hour
+	defaultIdempotencyTTL           = 24 * time.Hour
+	defaultIdempotencyPurgeInterval = time.Hour
+	defaultAuthLeeway               = 30 * time.Second
+)
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -48,9 +83,27 @@ func Load(path string) (Config, error) {
 		return Config{}, err
 	}
 
+	trashCfg, err := loadTrashConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	idempotencyCfg, err := loadIdempotencyConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	authCfg, err := loadAuthConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
 	return Config{
-		Server:   serverCfg,
-		Database: databaseCfg,
+		Server:      serverCfg,
+		Database:    databaseCfg,
+		Trash:       trashCfg,
+		Idempotency: idempotencyCfg,
+		Auth:        authCfg,
 	}, nil
 }
 
@@ -153,6 +206,92 @@ func loadDatabaseConfig() (DatabaseConfig, error) {
 	return cfg, nil
 }
 
+// loadTrashConfig reads the optional TRASH_RETENTION and TRASH_PURGE_INTERVAL durations.
+func loadTrashConfig() (TrashConfig, error) {
+	retention, err := optionalDuration("TRASH_RETENTION", defaultTrashRetention)
+	if err != nil {
+		return TrashConfig{}, err
+	}
+
+	purgeInterval, err := optionalDuration("TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval)
+	if err != nil {
+		return TrashConfig{}, err
+	}
+
+	return TrashConfig{
+		Retention:     retention,
+		PurgeInterval: purgeInterval,
+	}, nil
+}
+
+// loadIdempotencyConfig reads the optional IDEMPOTENCY_TTL and IDEMPOTENCY_PURGE_INTERVAL durations.
+func loadIdempotencyConfig() (IdempotencyConfig, error) {
+	ttl, err := optionalDuration("IDEMPOTENCY_TTL", defaultIdempotencyTTL)
+	if err != nil {
+		return IdempotencyConfig{}, err
+	}
+
+	purgeInterval, err := optionalDuration("IDEMPOTENCY_PURGE_INTERVAL", defaultIdempotencyPurgeInterval)
+	if err != nil {
+		return IdempotencyConfig{}, err
+	}
+
+	return IdempotencyConfig{
+		TTL:           ttl,
+		PurgeInterval: purgeInterval,
+	}, nil
+}
+
+// loadAuthConfig reads AUTH_JWT_KEY_FILE or AUTH_JWKS_FILE, AUTH_ISSUER, AUTH_AUDIENCE and the
+// optional AUTH_LEEWAY.
+func loadAuthConfig() (AuthConfig, error) {
+	keyFile := os.Getenv("AUTH_JWT_KEY_FILE")
+	jwksFile := os.Getenv("AUTH_JWKS_FILE")
+	if (keyFile == "") == (jwksFile == "") {
+		return AuthConfig{}, fmt.Errorf("exactly one of env variables %q and %q must be set", "AUTH_JWT_KEY_FILE", "AUTH_JWKS_FILE")
+	}
+
+	issuer := os.Getenv("AUTH_ISSUER")
+	if issuer == "" {
+		return AuthConfig{}, fmt.Errorf("env variable %q is not set", "AUTH_ISSUER")
+	}
+
+	audience := os.Getenv("AUTH_AUDIENCE")
+	if audience == "" {
+		return AuthConfig{}, fmt.Errorf("env variable %q is not set", "AUTH_AUDIENCE")
+	}
+
+	leeway, err := optionalDuration("AUTH_LEEWAY", defaultAuthLeeway)
+	if err != nil {
+		return AuthConfig{}, err
+	}
+
+	return AuthConfig{
+		KeyFile:  keyFile,
+		JWKSFile: jwksFile,
+		Issuer:   issuer,
+		Audience: audience,
+		Leeway:   leeway,
+	}, nil
+}
+
+func optionalDuration(key string, fallback time.Duration) (time.Duration, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s as duration: %w", key, err)
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("env variable %q must be positive", key)
+	}
+
+	return value, nil
+}
+
 func (c DatabaseConfig) DSN() string {
 	u := &url.URL{
 		Scheme: "postgres",
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+const (
+	// APIKeyPrefix starts every API key secret, so that a leaked key is easy to recognise.
+	APIKeyPrefix = "sk_"
+	// MaxAPIKeyNameLength bounds the name of an API key.
+	MaxAPIKeyNameLength = 100
+)
+
+// APIKey lets a machine client call the API without a user token. Only a hash of its secret is
+// stored; the secret itself is shown once, when the key is created. UserID is the user the key
+// acts for; only keys with the admin permission may leave it empty.
+type APIKey struct {
+	ID         string
+	Name       string
+	UserID     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// Actor is how changes made with the key are attributed in the audit trail.
+func (k APIKey) Actor() string {
+	return "api-key:" + k.ID
+}
+
+// Principal is the caller a request made with the key acts as.
+func (k APIKey) Principal() Principal {
+	return Principal{UserID: k.UserID, Scopes: k.Scopes}
+}
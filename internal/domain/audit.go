@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// SystemActor is recorded for changes made without an authenticated caller, e.g. by background jobs.
+const SystemActor = "system"
+
+type EventAction string
+
+const (
+	EventCreate      EventAction = "create"
+	EventUpdate      EventAction = "update"
+	EventDelete      EventAction = "delete"
+	EventRestore     EventAction = "restore"
+	EventPurge       EventAction = "purge"
+	EventCancel      EventAction = "cancel"
+	EventPause       EventAction = "pause"
+	EventResume      EventAction = "resume"
+	EventPriceChange EventAction = "price_change"
+)
+
+// SubscriptionEvent is an entry of the append-only audit trail. Before and After are JSON
+// snapshots of the subscription, with its pauses and price changes; Before is nil on create and
+// After is nil on purge.
+type SubscriptionEvent struct {
+	ID             int64
+	SubscriptionID string
+	Action         EventAction
+	Before         json.RawMessage
+	After          json.RawMessage
+	Actor          string
+	RequestID      string
+	CreatedAt      time.Time
+}
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+// WithActor returns a context whose changes are attributed to actor in the audit trail.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or SystemActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,51 @@
+package domain
+
+// MaxBatchSize bounds the number of operations in a single batch.
+const MaxBatchSize = 1000
+
+type BatchAction string
+
+const (
+	BatchCreate BatchAction = "create"
+	BatchUpdate BatchAction = "update"
+	BatchDelete BatchAction = "delete"
+)
+
+// BatchMode decides what happens to a batch when one of its operations fails.
+type BatchMode string
+
+const (
+	// BatchAtomic applies all operations in one transaction, or none of them.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort applies every operation on its own, whatever happens to the others.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// BatchOperation is a single create, update or delete in a batch. Subscription is the new state for
+// create and update. A non-zero Version makes update and delete conditional, like If-Match.
+type BatchOperation struct {
+	Action       BatchAction
+	ID           string
+	Version      int64
+	Subscription Subscription
+}
+
+type BatchStatus string
+
+const (
+	BatchStatusOK BatchStatus = "ok"
+	// BatchStatusFailed is an operation that returned Err.
+	BatchStatusFailed BatchStatus = "failed"
+	// BatchStatusRolledBack is an operation that succeeded but was undone with a failed atomic batch.
+	BatchStatusRolledBack BatchStatus = "rolled_back"
+	// BatchStatusSkipped is an operation not attempted after an earlier one failed an atomic batch.
+	BatchStatusSkipped BatchStatus = "skipped"
+)
+
+// BatchResult is the outcome of the operation at the same index. ID is the created subscription on create.
+type BatchResult struct {
+	Action BatchAction
+	ID     string
+	Status BatchStatus
+	Err    error
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
 
 var ErrNotImplemented = errors.New("not implemented")
 
@@ -10,12 +14,61 @@ var (
 	ErrInvalidID             = errors.New("invalid id")
 	ErrInvalidServiceName    = errors.New("invalid service name")
 	ErrInvalidUserID         = errors.New("invalid user id")
+	ErrForeignUserID         = errors.New("foreign user id")
 	ErrInvalidPrice          = errors.New("invalid price")
+	ErrInvalidCurrency       = errors.New("invalid currency")
+	ErrInvalidBillingPeriod  = errors.New("invalid billing period")
+	ErrInvalidExchangeRate   = errors.New("invalid exchange rate")
+	ErrExchangeRateNotFound  = errors.New("exchange rate not found")
+	ErrMissingExchangeRate   = errors.New("missing exchange rate")
 	ErrInvalidStartDate      = errors.New("invalid start date")
 	ErrInvalidEndDate        = errors.New("invalid end date")
+	ErrInvalidTrialEnd       = errors.New("invalid trial end")
 	ErrInvalidFromDate       = errors.New("invalid from date")
 	ErrInvalidToDate         = errors.New("invalid to date")
 	ErrInvalidPeriod         = errors.New("invalid period")
+	ErrPeriodTooLong         = errors.New("period too long")
+	ErrInvalidLimit          = errors.New("invalid limit")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrInvalidSort           = errors.New("invalid sort")
+	ErrInvalidMinPrice       = errors.New("invalid min price")
+	ErrInvalidMaxPrice       = errors.New("invalid max price")
+	ErrInvalidPriceRange     = errors.New("invalid price range")
+	ErrInvalidActiveMonth    = errors.New("invalid active month")
+	ErrInvalidStartRange     = errors.New("invalid start date range")
+	ErrInvalidEndRange       = errors.New("invalid end date range")
+	ErrInvalidOpenEnded      = errors.New("invalid open ended flag")
+	ErrInvalidSearch         = errors.New("invalid search")
+	ErrInvalidTrialWindow    = errors.New("invalid trial ends within")
+	ErrInvalidGroupBy        = errors.New("invalid group by")
+	ErrInvalidProration      = errors.New("invalid proration")
+	ErrInvalidEffectiveFrom  = errors.New("invalid effective from date")
+	ErrInvalidPauseDate      = errors.New("invalid pause date")
+	ErrInvalidResumeDate     = errors.New("invalid resume date")
+	ErrSubscriptionPaused    = errors.New("subscription is already paused")
+	ErrSubscriptionNotPaused = errors.New("subscription is not paused")
+	ErrInvalidTransition     = errors.New("invalid status transition")
+	ErrInvalidStatus         = errors.New("invalid status")
+	ErrInvalidCancelMonth    = errors.New("invalid cancellation month")
+	ErrInvalidCancelReason   = errors.New("invalid cancellation reason")
+	ErrPreconditionFailed    = errors.New("subscription has been modified")
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInUse   = errors.New("request with this idempotency key is still in progress")
+	ErrInvalidBatchSize      = errors.New("invalid batch size")
+	ErrInvalidBatchMode      = errors.New("invalid batch mode")
+	ErrInvalidBatchAction    = errors.New("invalid batch action")
+	ErrInvalidCSV            = errors.New("invalid csv")
+	ErrImportTooLarge        = errors.New("import too large")
+	ErrInvalidDryRun         = errors.New("invalid dry run flag")
+	ErrInvalidExportFormat   = errors.New("invalid export format")
+	ErrInvalidAPIKeyName     = errors.New("invalid api key name")
+	ErrInvalidScope          = errors.New("invalid scope")
+	ErrMissingUserID         = errors.New("missing user id")
+	ErrInvalidExpiry         = errors.New("invalid expiry")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
+	ErrInvalidAPIKey         = errors.New("invalid api key")
+	ErrForbidden             = errors.New("forbidden")
 )
 
 type ValidationError struct {
@@ -32,3 +85,106 @@ func (v *ValidationError) Error() string {
 func (v *ValidationError) Unwrap() error {
 	return v.Err
 }
+
+// Fields returns the field errors carried by v, in order. Err may be a single FieldError or
+// FieldErrors; an error without field details is returned as a FieldError with an empty Field.
+func (v *ValidationError) Fields() []FieldError {
+	if v == nil || v.Err == nil {
+		return nil
+	}
+
+	var fields []FieldError
+	var collect func(err error)
+	collect = func(err error) {
+		var fieldErr *FieldError
+		switch e := err.(type) {
+		case FieldErrors:
+			for _, fieldErr := range e {
+				collect(fieldErr)
+			}
+		case *FieldError:
+			fields = append(fields, *e)
+		default:
+			if errors.As(err, &fieldErr) {
+				fields = append(fields, *fieldErr)
+				return
+			}
+			fields = append(fields, FieldError{Code: ErrorCode(err), Err: err})
+		}
+	}
+	collect(v.Err)
+
+	return fields
+}
+
+// FieldError is an invalid input field. Field is the path of the field in the request, such as
+// "price" or "operations[2].subscription.price", and Code names Err for machines.
+type FieldError struct {
+	Field string
+	Code  string
+	Err   error
+}
+
+// NewFieldError returns a FieldError for field with the code of err.
+func NewFieldError(field string, err error) *FieldError {
+	return &FieldError{Field: field, Code: ErrorCode(err), Err: err}
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is a multi-error of every invalid field of an input, in the order they were found.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// Add records an invalid field.
+func (e *FieldErrors) Add(field string, err error) {
+	*e = append(*e, NewFieldError(field, err))
+}
+
+// Err returns the collected field errors as a ValidationError, or nil if there are none.
+func (e FieldErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &ValidationError{Err: e}
+}
+
+// ErrorCode is the machine-readable name of err: the message of the sentinel error it wraps, in
+// snake case, such as "invalid_price".
+func ErrorCode(err error) string {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			break
+		}
+		err = inner
+	}
+
+	return strings.Join(strings.FieldsFunc(strings.ToLower(err.Error()), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 alphabetic code.
+func IsCurrencyCode(code string) bool {
+	return currencyPattern.MatchString(code)
+}
+
+// ExchangeRate converts amounts in Base into Quote: 1 Base = Rate Quote.
+// Rate is a decimal string to avoid float rounding.
+type ExchangeRate struct {
+	Base      string
+	Quote     string
+	Rate      string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "math"
+
+const MaxSearchLength = 100
+
+// MaxPrice is the largest price the integer price column holds.
+const MaxPrice = math.MaxInt32
+
+// MaxTrialWindowDays bounds ListFilter.TrialEndsWithin to ten years.
+const MaxTrialWindowDays = 3650
+
+// ListFilter narrows the subscription listing. Zero values mean "no filter".
+// Month values use the MM-YYYY format.
+type ListFilter struct {
+	UserID      string
+	ServiceName string
+	// Search is a case-insensitive substring of the service name.
+	Search    string
+	MinPrice  *int
+	MaxPrice  *int
+	ActiveIn  string
+	StartFrom string
+	StartTo   string
+	EndFrom   string
+	EndTo     string
+	// OpenEnded keeps only subscriptions without an end date.
+	OpenEnded bool
+	// TrialEndsWithin keeps only subscriptions whose trial ends between today and N days from now.
+	TrialEndsWithin *int
+	Status          SubscriptionStatus
+	// Deleted lists the trash instead of live subscriptions.
+	Deleted bool
+	// OwnerID restricts the listing to one user's subscriptions on top of UserID. The service sets
+	// it to the caller's user ID unless the caller may see every user's subscriptions.
+	OwnerID string
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// MaxIdempotencyKeyLength bounds the Idempotency-Key header.
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey is the first request an actor made with an Idempotency-Key. Response is nil while
+// that request is still being handled.
+type IdempotencyKey struct {
+	Actor       string
+	Key         string
+	RequestHash string
+	Response    *StoredResponse
+	ExpiresAt   time.Time
+}
+
+// StoredResponse is a response kept for replay to retries of the same request.
+type StoredResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,44 @@
+package domain
+
+// MaxImportErrors bounds the row errors kept in an import report; later ones are only counted.
+const MaxImportErrors = 1000
+
+const (
+	// MaxImportRows bounds the rows of a single import file.
+	MaxImportRows = 10000
+	// MaxImportBytes bounds the size of a single import file.
+	MaxImportBytes = 10 << 20
+)
+
+// ImportRow is a subscription read from an import file. Line is its line in the file, and Err is
+// set when the row could not be read into a subscription.
+type ImportRow struct {
+	Line         int
+	Subscription Subscription
+	Err          error
+	// Invalid lists the columns whose values could not be read into Subscription. The rest of the
+	// row is still validated so that every invalid column is reported at once.
+	Invalid FieldErrors
+}
+
+// ImportReport sums up an import. Nothing is imported unless every row is valid.
+type ImportReport struct {
+	DryRun   bool
+	Rows     int
+	Imported int
+	Failed   int
+	Errors   []ImportError
+}
+
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+// AddError records a failed row.
+func (r *ImportReport) AddError(line int, err error) {
+	r.Failed++
+	if len(r.Errors) < MaxImportErrors {
+		r.Errors = append(r.Errors, ImportError{Line: line, Err: err})
+	}
+}
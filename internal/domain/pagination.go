@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt   SortField = "created_at"
+	SortByPrice       SortField = "price"
+	SortByStartDate   SortField = "start_date"
+	SortByServiceName SortField = "service_name"
+)
+
+// Sort is a single-column ordering. Ties are always broken by id in the same direction.
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// String returns the sort in its query form, e.g. "price" or "-created_at".
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// PageRequest is the raw pagination input as received from the client.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   string
+}
+
+// Pagination is a validated PageRequest.
+type Pagination struct {
+	Limit int
+	Sort  Sort
+	After *Cursor
+}
+
+// Cursor points at the last row of a page: its sort key and id.
+// Sort is kept so that a cursor cannot be replayed against a different ordering.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// Encode returns the opaque representation of the cursor handed out to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(raw string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, err
+	}
+	return c, nil
+}
+
+type SubscriptionPage struct {
+	Items      []Subscription
+	NextCursor string
+}
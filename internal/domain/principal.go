@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal is the authenticated caller: the user it acts as, its roles and the scopes it was
+// granted. A scope naming a permission grants that permission directly.
+type Principal struct {
+	UserID string
+	Roles  []string
+	Scopes []string
+}
+
+// Can reports whether one of the principal's roles or scopes grants perm. PermAdmin grants every
+// permission.
+func (p Principal) Can(perm Permission) bool {
+	return p.granted(perm) || p.granted(PermAdmin)
+}
+
+func (p Principal) granted(perm Permission) bool {
+	if slices.Contains(p.Scopes, string(perm)) {
+		return true
+	}
+	for _, name := range p.Roles {
+		if slices.Contains(Roles[name].Permissions, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllUsers reports whether the principal may reach every user's subscriptions.
+func (p Principal) AllUsers() bool {
+	if p.Can(PermAdmin) {
+		return true
+	}
+	for _, name := range p.Roles {
+		if Roles[name].AllUsers {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context for requests made by p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal set by WithPrincipal. There is none for work the
+// service does on its own, such as background jobs.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
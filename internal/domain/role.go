@@ -0,0 +1,48 @@
+package domain
+
+// Permission allows a group of API routes.
+type Permission string
+
+const (
+	PermSubscriptionsRead  Permission = "subscriptions:read"
+	PermSubscriptionsWrite Permission = "subscriptions:write"
+	PermReportsRead        Permission = "reports:read"
+	// PermAdmin allows the admin routes and acting on every user's subscriptions. It implies every
+	// other permission.
+	PermAdmin Permission = "admin"
+)
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleFinance = "finance"
+	RoleAdmin   = "admin"
+)
+
+// Role is a named set of permissions.
+type Role struct {
+	Permissions []Permission
+	// AllUsers lets the role reach every user's subscriptions instead of only its own.
+	AllUsers bool
+}
+
+// Roles maps each role to what it may do. Support staff read subscriptions without changing them,
+// and finance sees totals and exports but not the raw list.
+var Roles = map[string]Role{
+	RoleUser:    {Permissions: []Permission{PermSubscriptionsRead, PermSubscriptionsWrite, PermReportsRead}},
+	RoleSupport: {Permissions: []Permission{PermSubscriptionsRead}, AllUsers: true},
+	RoleFinance: {Permissions: []Permission{PermReportsRead}, AllUsers: true},
+	RoleAdmin: {
+		Permissions: []Permission{PermSubscriptionsRead, PermSubscriptionsWrite, PermReportsRead, PermAdmin},
+		AllUsers:    true,
+	},
+}
+
+// IsPermission reports whether scope names a permission.
+func IsPermission(scope string) bool {
+	switch Permission(scope) {
+	case PermSubscriptionsRead, PermSubscriptionsWrite, PermReportsRead, PermAdmin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "fmt"
+
+// SubscriptionStatus is derived from the stored dates and history rather than stored itself:
+// once its end date has passed a subscription is cancelled if a cancellation was recorded and
+// expired otherwise; before that it is paused while a pause covers today.
+type SubscriptionStatus string
+
+const (
+	StatusActive    SubscriptionStatus = "active"
+	StatusCancelled SubscriptionStatus = "cancelled"
+	StatusExpired   SubscriptionStatus = "expired"
+	StatusPaused    SubscriptionStatus = "paused"
+)
+
+// transitions lists the statuses an action may move a subscription to. Pauses may be scheduled
+// ahead, so pausing or resuming is allowed from both active and paused. Cancelled and expired
+// are final.
+var transitions = map[SubscriptionStatus][]SubscriptionStatus{
+	StatusActive: {StatusActive, StatusPaused, StatusCancelled},
+	StatusPaused: {StatusActive, StatusPaused, StatusCancelled},
+}
+
+func IsSubscriptionStatus(status SubscriptionStatus) bool {
+	switch status {
+	case StatusActive, StatusCancelled, StatusExpired, StatusPaused:
+		return true
+	}
+	return false
+}
+
+// CheckTransition returns a *TransitionError unless a subscription in status from may be moved to status to.
+func CheckTransition(from SubscriptionStatus, to SubscriptionStatus) error {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &TransitionError{From: from, To: to}
+}
+
+// TransitionError reports an action the subscription's current status does not allow.
+// It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From SubscriptionStatus
+	To   SubscriptionStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s subscription cannot become %s", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+const MaxCancelReasonLength = 500
+
+// Cancellation ends a subscription on the last day of EffectiveMonth (MM-YYYY).
+type Cancellation struct {
+	EffectiveMonth string
+	Reason         string
+}
@@ -1,10 +1,104 @@
 package domain
 
+import "time"
+
+// DefaultCurrency is used when a subscription is given without a currency, and for totals over
+// several currencies when none was requested.
+const DefaultCurrency = "RUB"
+
+// BillingPeriod is how often a subscription is charged. The first charge is on the start date.
+type BillingPeriod string
+
+const (
+	BillingWeekly    BillingPeriod = "weekly"
+	BillingMonthly   BillingPeriod = "monthly"
+	BillingQuarterly BillingPeriod = "quarterly"
+	BillingYearly    BillingPeriod = "yearly"
+)
+
+// Subscription dates are days in the YYYY-MM-DD format once validated; both are inclusive.
+// On input and output a whole month may also be written as MM-YYYY: the first day of the month
+// for StartDate and the last day of the month for EndDate.
 type Subscription struct {
 	ID          string
 	ServiceName string
 	Price       int
-	UserID      string
-	StartDate   string
-	EndDate     *string
+	// Currency is an ISO 4217 code, e.g. "EUR".
+	Currency      string
+	BillingPeriod BillingPeriod
+	UserID        string
+	StartDate     string
+	EndDate       *string
+	// TrialEnd is the last day of a free trial, written like EndDate. Charges up to it are free.
+	TrialEnd *string
+	// Status is computed when the subscription is read and ignored on writes.
+	Status       SubscriptionStatus
+	CancelledAt  *time.Time
+	CancelReason string
+	// DeletedAt is set while the subscription is in the trash.
+	DeletedAt *time.Time
+	// Pauses is the pause history, oldest first. It is only loaded for a single subscription.
+	Pauses []Pause
+	// Version is bumped by every change. On update a non-zero Version is the version the caller
+	// last saw, and the update fails with ErrPreconditionFailed if the subscription has moved on.
+	Version int64
+}
+
+// Pause is an interval without charges: from PausedFrom (inclusive) to ResumedFrom (exclusive).
+// ResumedFrom is nil while the subscription is still paused.
+type Pause struct {
+	PausedFrom  string
+	ResumedFrom *string
+}
+
+// PriceChange sets the price of a subscription from EffectiveFrom (inclusive) until the next change.
+// Charges before the first change use the subscription's own Price.
+type PriceChange struct {
+	SubscriptionID string
+	Price          int
+	EffectiveFrom  string
+}
+
+// SubscriptionPatch is a partial update: nil fields are left as they are. EndDate and TrialEnd
+// are set when the patch touches them, and an inner nil clears the date. A non-zero Version works
+// as on Subscription.
+type SubscriptionPatch struct {
+	Version       int64
+	ServiceName   *string
+	Price         *int
+	Currency      *string
+	BillingPeriod *BillingPeriod
+	UserID        *string
+	StartDate     *string
+	EndDate       **string
+	TrialEnd      **string
+}
+
+// Apply returns sub with the patch merged in.
+func (p SubscriptionPatch) Apply(sub Subscription) Subscription {
+	if p.ServiceName != nil {
+		sub.ServiceName = *p.ServiceName
+	}
+	if p.Price != nil {
+		sub.Price = *p.Price
+	}
+	if p.Currency != nil {
+		sub.Currency = *p.Currency
+	}
+	if p.BillingPeriod != nil {
+		sub.BillingPeriod = *p.BillingPeriod
+	}
+	if p.UserID != nil {
+		sub.UserID = *p.UserID
+	}
+	if p.StartDate != nil {
+		sub.StartDate = *p.StartDate
+	}
+	if p.EndDate != nil {
+		sub.EndDate = *p.EndDate
+	}
+	if p.TrialEnd != nil {
+		sub.TrialEnd = *p.TrialEnd
+	}
+	return sub
 }
@@ -0,0 +1,58 @@
+package domain
+
+// MaxPeriodMonths bounds the period of a total, breakdown or timeseries, and so the number of
+// points a single timeseries request may produce.
+const MaxPeriodMonths = 120
+
+// Proration controls how a charge whose billing period is cut short by the end date is counted.
+type Proration string
+
+const (
+	// ProrationNone charges the full price for every charge.
+	ProrationNone Proration = "none"
+	// ProrationDaily charges only the share of the billing period's days the subscription is active.
+	ProrationDaily Proration = "daily"
+)
+
+// TotalFilter selects the subscriptions and the period of a total, breakdown or timeseries.
+// From and To are months in the MM-YYYY format, both inclusive.
+type TotalFilter struct {
+	UserID      string
+	ServiceName string
+	From        string
+	To          string
+	// Currency is the currency of the result. Empty means none was requested: the result is then
+	// in the currency every selected subscription is billed in, or DefaultCurrency when they differ.
+	Currency  string
+	Proration Proration
+	// OwnerID works as on ListFilter.
+	OwnerID string
+}
+
+type TotalGroupBy string
+
+const (
+	GroupByServiceName TotalGroupBy = "service_name"
+	GroupByUserID      TotalGroupBy = "user_id"
+	GroupByMonth       TotalGroupBy = "month"
+	GroupByCurrency    TotalGroupBy = "currency"
+)
+
+// TotalBucket is one group of a total breakdown. Buckets of a breakdown sum to the plain total
+// and are in its currency, except in a breakdown by currency where each bucket keeps the currency
+// named by its key.
+type TotalBucket struct {
+	Key    string
+	Amount int64
+	// Count is the number of subscriptions contributing to the bucket.
+	Count int64
+}
+
+// TimeseriesPoint is the spend and subscription movement of a single calendar month.
+type TimeseriesPoint struct {
+	Month   string
+	Amount  int64
+	Active  int64
+	Started int64
+	Ended   int64
+}
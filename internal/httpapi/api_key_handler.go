@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"subscription_service/pkg/logger"
+)
+
+type APIKeyHandler struct {
+	log     logger.Logger
+	service apiKeyService
+}
+
+func NewAPIKeyHandler(log logger.Logger, service apiKeyService) *APIKeyHandler {
+	return &APIKeyHandler{log: log, service: service}
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Issue a key for a machine client. The secret is only returned in this response;
+// @Description send it in the X-API-Key header. Requires the admin permission.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body APIKeyRequest true "API key"
+// @Success 201 {object} CreatedAPIKeyResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var reqDTO APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	key, secret, err := h.service.Create(r.Context(), reqDTO.toDomain())
+	if err != nil {
+		handleError(h.log, w, r, err, "create api key")
+		return
+	}
+
+	resp := CreatedAPIKeyResponse{APIKeyResponse: fromDomainAPIKey(key), Secret: secret}
+	if err := writeJSON(w, http.StatusCreated, resp); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Revoked and expired keys are listed too. Secrets are never returned. Requires the admin permission.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} APIKeyResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.List(r.Context())
+	if err != nil {
+		handleError(h.log, w, r, err, "list api keys")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainAPIKeys(keys)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description The key stops working immediately and cannot be restored. Requires the admin permission.
+// @Tags admin
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Revoke(r.Context(), chi.URLParam(r, "id")); err != nil {
+		handleError(h.log, w, r, err, "revoke api key")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "ok"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package httpapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	"subscription_service/internal/httpapi"
+	"subscription_service/pkg/logger"
+)
+
+const testAPIKey = domain.APIKeyPrefix + "test-secret"
+
+func newAPIKeysRouter(
+	ctrl *gomock.Controller, svc *MocksubscriptionService, keys *MockapiKeyService, authn *MockapiKeyAuthenticator,
+) http.Handler {
+	log := logger.NewNoop()
+	return httpapi.NewHandler(
+		log,
+		httpapi.NewSubscriptionHandler(log, svc),
+		httpapi.NewExchangeRateHandler(log, NewMockexchangeRateService(ctrl)),
+		httpapi.NewAPIKeyHandler(log, keys),
+		httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+		newTestAuth(),
+		httpapi.NewAPIKeyMiddleware(log, authn),
+	)
+}
+
+func adminToken() string {
+	claims := testClaims(testSubject)
+	claims.Roles = []string{domain.RoleAdmin}
+	return "Bearer " + signTestToken(claims)
+}
+
+func TestCreateAPIKey_ShowsSecretOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keys := NewMockapiKeyService(ctrl)
+	keys.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+			require.Equal(t, "billing", key.Name)
+			require.Equal(t, []string{"admin"}, key.Scopes)
+			key.ID = uuid.NewString()
+			key.CreatedAt = time.Now()
+			return key, testAPIKey, nil
+		})
+	keys.EXPECT().List(gomock.Any()).Return([]domain.APIKey{{ID: uuid.NewString(), Name: "billing"}}, nil)
+	h := newAPIKeysRouter(ctrl, NewMocksubscriptionService(ctrl), keys, NewMockapiKeyAuthenticator(ctrl))
+
+	body := []byte(`{"name":"billing","scopes":["admin"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/api-keys/", bytes.NewReader(body))
+	req.Header.Set("Authorization", adminToken())
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created httpapi.CreatedAPIKeyResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	require.Equal(t, testAPIKey, created.Secret)
+	require.Equal(t, "billing", created.Name)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/api-keys/", nil)
+	req.Header.Set("Authorization", adminToken())
+	w = httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "secret")
+}
+
+func TestAPIKeys_RequireAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newAPIKeysRouter(ctrl, NewMocksubscriptionService(ctrl), NewMockapiKeyService(ctrl), NewMockapiKeyAuthenticator(ctrl))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/api-keys/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(testClaims(testSubject)))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, http.StatusForbidden, resp.Status)
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	keys := NewMockapiKeyService(ctrl)
+	keys.EXPECT().Revoke(gomock.Any(), id).Return(domain.ErrAPIKeyNotFound)
+	h := newAPIKeysRouter(ctrl, NewMocksubscriptionService(ctrl), keys, NewMockapiKeyAuthenticator(ctrl))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/api-keys/"+id, nil)
+	req.Header.Set("Authorization", adminToken())
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := domain.APIKey{ID: uuid.NewString(), Name: "billing", UserID: uuid.NewString(), Scopes: []string{"admin"}}
+	authn := NewMockapiKeyAuthenticator(ctrl)
+	authn.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(key, nil)
+	authn.EXPECT().Authenticate(gomock.Any(), domain.APIKeyPrefix+"revoked").Return(domain.APIKey{}, domain.ErrInvalidAPIKey)
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Delete(gomock.Any(), gomock.Any(), int64(0)).
+		DoAndReturn(func(ctx context.Context, _ string, _ int64) error {
+			principal, ok := domain.PrincipalFromContext(ctx)
+			require.True(t, ok)
+			require.Equal(t, key.Principal(), principal)
+			require.Equal(t, "api-key:"+key.ID, domain.ActorFromContext(ctx))
+			return nil
+		})
+	h := newAPIKeysRouter(ctrl, svc, NewMockapiKeyService(ctrl), authn)
+
+	// The key is enough on its own, without a bearer token.
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+	req.Header.Set(httpapi.APIKeyHeader, testAPIKey)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+	req.Header.Set(httpapi.APIKeyHeader, domain.APIKeyPrefix+"revoked")
+	w = httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,236 @@
+package httpapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	"subscription_service/internal/httpapi"
+	"subscription_service/pkg/jwt"
+	"subscription_service/pkg/logger"
+)
+
+const (
+	testIssuer   = "https://auth.example.com"
+	testAudience = "subscriptions"
+	testSubject  = "test-user"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestAuth() *httpapi.AuthMiddleware {
+	key, err := jwt.NewHMACKey("", testSecret)
+	if err != nil {
+		panic(err)
+	}
+	verifier := jwt.NewVerifier([]jwt.Key{key}, jwt.Expectations{Issuer: testIssuer, Audience: testAudience})
+	return httpapi.NewAuthMiddleware(logger.NewNoop(), verifier)
+}
+
+func testClaims(subject string) jwt.Claims {
+	return jwt.Claims{
+		Issuer:    testIssuer,
+		Subject:   subject,
+		Audience:  jwt.Audience{testAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func signTestToken(claims jwt.Claims) string {
+	token, err := jwt.Sign(claims, "", testSecret)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// authenticated signs requests that carry no Authorization header in as testSubject with roles,
+// or with the default user role if none are given.
+func authenticated(h http.Handler, roles ...string) http.Handler {
+	claims := testClaims(testSubject)
+	claims.Roles = roles
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+signTestToken(claims))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestAuth_Rejects(t *testing.T) {
+	expired := testClaims(testSubject)
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	foreign := testClaims(testSubject)
+	foreign.Audience = jwt.Audience{"billing"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		challenge     string
+	}{
+		{name: "missing", authorization: "", challenge: `Bearer`},
+		{name: "basic scheme", authorization: "Basic dXNlcjpwYXNz", challenge: `Bearer`},
+		{name: "garbage", authorization: "Bearer not-a-token", challenge: `Bearer error="invalid_token"`},
+		{name: "expired", authorization: "Bearer " + signTestToken(expired), challenge: `Bearer error="invalid_token"`},
+		{name: "wrong audience", authorization: "Bearer " + signTestToken(foreign), challenge: `Bearer error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			log := logger.NewNoop()
+			h := httpapi.NewHandler(
+				log,
+				httpapi.NewSubscriptionHandler(log, NewMocksubscriptionService(ctrl)),
+				httpapi.NewExchangeRateHandler(log, NewMockexchangeRateService(ctrl)),
+				httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+				httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+				newTestAuth(),
+				httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusUnauthorized, w.Code)
+			require.Equal(t, tt.challenge, w.Header().Get("WWW-Authenticate"))
+			require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestAuth_HealthIsPublic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	log := logger.NewNoop()
+	h := httpapi.NewHandler(
+		log,
+		httpapi.NewSubscriptionHandler(log, NewMocksubscriptionService(ctrl)),
+		httpapi.NewExchangeRateHandler(log, NewMockexchangeRateService(ctrl)),
+		httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+		httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+		newTestAuth(),
+		httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+	)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/health", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuth_SubjectIsTheActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Delete(gomock.Any(), gomock.Any(), int64(0)).
+		DoAndReturn(func(ctx context.Context, _ string, _ int64) error {
+			require.Equal(t, "alice", domain.ActorFromContext(ctx))
+			return nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(testClaims("alice")))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuth_ScopesReachThePrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Delete(gomock.Any(), gomock.Any(), int64(0)).
+		DoAndReturn(func(ctx context.Context, _ string, _ int64) error {
+			principal, ok := domain.PrincipalFromContext(ctx)
+			require.True(t, ok)
+			require.Equal(t, domain.Principal{
+				UserID: "alice",
+				Roles:  []string{domain.RoleUser},
+				Scopes: []string{"admin", "reports:read"},
+			}, principal)
+			require.True(t, principal.Can(domain.PermAdmin))
+			return nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	claims := testClaims("alice")
+	claims.Scope = "admin reports:read"
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(claims))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuth_RolePermissions(t *testing.T) {
+	tests := []struct {
+		role   string
+		method string
+		path   string
+		want   int
+	}{
+		{role: domain.RoleUser, method: http.MethodGet, path: "/api/v1/subscriptions/", want: http.StatusOK},
+		{role: domain.RoleUser, method: http.MethodDelete, path: "/api/v1/subscriptions/" + uuid.NewString(), want: http.StatusOK},
+		{role: domain.RoleUser, method: http.MethodGet, path: "/api/v1/admin/exchange-rates/", want: http.StatusForbidden},
+		{role: domain.RoleSupport, method: http.MethodGet, path: "/api/v1/subscriptions/", want: http.StatusOK},
+		{role: domain.RoleSupport, method: http.MethodDelete, path: "/api/v1/subscriptions/" + uuid.NewString(), want: http.StatusForbidden},
+		{role: domain.RoleSupport, method: http.MethodGet, path: "/api/v1/subscriptions/total?from=07-2025&to=08-2025", want: http.StatusForbidden},
+		{role: domain.RoleFinance, method: http.MethodGet, path: "/api/v1/subscriptions/total?from=07-2025&to=08-2025", want: http.StatusOK},
+		{role: domain.RoleFinance, method: http.MethodGet, path: "/api/v1/subscriptions/", want: http.StatusForbidden},
+		{role: domain.RoleAdmin, method: http.MethodGet, path: "/api/v1/admin/exchange-rates/", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc := NewMocksubscriptionService(ctrl)
+			svc.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.SubscriptionPage{}, nil).AnyTimes()
+			svc.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			svc.EXPECT().Total(gomock.Any(), gomock.Any()).Return(int64(0), domain.DefaultCurrency, nil).AnyTimes()
+			rates := NewMockexchangeRateService(ctrl)
+			rates.EXPECT().List(gomock.Any()).Return(nil, nil).AnyTimes()
+
+			log := logger.NewNoop()
+			h := httpapi.NewHandler(
+				log,
+				httpapi.NewSubscriptionHandler(log, svc),
+				httpapi.NewExchangeRateHandler(log, rates),
+				httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+				httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+				newTestAuth(),
+				httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+			)
+
+			claims := testClaims(testSubject)
+			claims.Roles = []string{tt.role}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+signTestToken(claims))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want == http.StatusForbidden {
+				require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
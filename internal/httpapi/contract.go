@@ -2,16 +2,58 @@ package httpapi
 
 import (
 	"context"
+	"iter"
 
 	"subscription_service/internal/domain"
+	"subscription_service/pkg/jwt"
 )
 
 //go:generate mockgen -source=contract.go -destination=mock_test.go -package=httpapi_test
 type subscriptionService interface {
 	Create(ctx context.Context, sub domain.Subscription) (string, error)
 	GetByID(ctx context.Context, id string) (domain.Subscription, error)
-	List(ctx context.Context, userID string, serviceName string) ([]domain.Subscription, error)
+	List(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error)
+	Export(ctx context.Context, filter domain.ListFilter) (iter.Seq2[domain.Subscription, error], error)
 	Update(ctx context.Context, sub domain.Subscription) error
-	Delete(ctx context.Context, id string) error
-	Total(ctx context.Context, filter domain.Subscription) (int64, error)
+	Patch(ctx context.Context, id string, patch domain.SubscriptionPatch) error
+	Import(ctx context.Context, rows iter.Seq2[domain.ImportRow, error], dryRun bool) (domain.ImportReport, error)
+	Batch(ctx context.Context, mode domain.BatchMode, ops []domain.BatchOperation) ([]domain.BatchResult, error)
+	Delete(ctx context.Context, id string, version int64) error
+	ListTrash(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error)
+	Restore(ctx context.Context, id string) error
+	Pause(ctx context.Context, id string, from string) error
+	Resume(ctx context.Context, id string, from string) error
+	Cancel(ctx context.Context, id string, cancellation domain.Cancellation) error
+	ChangePrice(ctx context.Context, change domain.PriceChange) error
+	ListPriceChanges(ctx context.Context, id string) ([]domain.PriceChange, error)
+	History(ctx context.Context, id string) ([]domain.SubscriptionEvent, error)
+	Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error)
+	TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy string) ([]domain.TotalBucket, string, error)
+	Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error)
+}
+
+type exchangeRateService interface {
+	Set(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error)
+	List(ctx context.Context) ([]domain.ExchangeRate, error)
+	Delete(ctx context.Context, base string, quote string) error
+}
+
+type apiKeyService interface {
+	Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+}
+
+type apiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (domain.APIKey, error)
+}
+
+type tokenVerifier interface {
+	Verify(token string) (jwt.Claims, error)
+}
+
+type idempotencyService interface {
+	Begin(ctx context.Context, actor string, key string, requestHash string) (*domain.StoredResponse, error)
+	Complete(ctx context.Context, actor string, key string, resp domain.StoredResponse) error
+	Release(ctx context.Context, actor string, key string) error
 }
@@ -0,0 +1,151 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"subscription_service/internal/domain"
+)
+
+// csvColumns are the columns of an import file, named like the SubscriptionRequest fields.
+var csvColumns = []string{
+	"service_name", "price", "currency", "billing_period", "user_id", "start_date", "end_date", "trial_end",
+}
+
+var requiredCSVColumns = []string{"service_name", "price", "user_id", "start_date"}
+
+// readSubscriptionsCSV reads the header of an import file and returns its rows one at a time.
+// Columns may come in any order; optional ones may be left out or left empty.
+func readSubscriptionsCSV(body io.Reader) (iter.Seq2[domain.ImportRow, error], error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, csvError("missing header")
+	}
+	if err != nil {
+		return nil, csvReadError(err)
+	}
+
+	columns, err := csvHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	fields := len(header)
+
+	return func(yield func(domain.ImportRow, error) bool) {
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+				// Malformed quoting leaves the reader unable to find the next row.
+				yield(domain.ImportRow{}, csvReadError(err))
+				return
+			}
+
+			line, _ := reader.FieldPos(0)
+			var row domain.ImportRow
+			if err != nil {
+				row = domain.ImportRow{Line: line, Err: csvError(fmt.Sprintf("expected %d fields, got %d", fields, len(record)))}
+			} else {
+				row = csvRow(line, columns, record)
+			}
+
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+// csvHeader maps column names to their positions.
+func csvHeader(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // byte order mark written by spreadsheets
+		}
+		name = strings.TrimSpace(name)
+
+		if !slices.Contains(csvColumns, name) {
+			return nil, csvError(fmt.Sprintf("unknown column %q", name))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, csvError(fmt.Sprintf("duplicate column %q", name))
+		}
+		columns[name] = i
+	}
+
+	for _, name := range requiredCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, csvError(fmt.Sprintf("missing column %q", name))
+		}
+	}
+
+	return columns, nil
+}
+
+func csvRow(line int, columns map[string]int, record []string) domain.ImportRow {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return csvUnescape(record[i])
+		}
+		return ""
+	}
+	optional := func(name string) *string {
+		if value := field(name); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	row := domain.ImportRow{Line: line}
+	price, err := strconv.Atoi(strings.TrimSpace(field("price")))
+	if err != nil {
+		row.Invalid.Add("price", domain.ErrInvalidPrice)
+	}
+
+	row.Subscription = domain.Subscription{
+		ServiceName:   field("service_name"),
+		Price:         price,
+		Currency:      field("currency"),
+		BillingPeriod: domain.BillingPeriod(field("billing_period")),
+		UserID:        field("user_id"),
+		StartDate:     field("start_date"),
+		EndDate:       optional("end_date"),
+		TrialEnd:      optional("trial_end"),
+	}
+	return row
+}
+
+// csvUnescape undoes csvCell, so an exported file imports back unchanged.
+func csvUnescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// csvReadError reports a file the reader gave up on, telling an oversized body from a malformed one.
+func csvReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w: more than %d bytes", domain.ErrImportTooLarge, tooLarge.Limit)
+	}
+	return csvError(err.Error())
+}
+
+func csvError(msg string) error {
+	return &domain.ValidationError{Err: fmt.Errorf("%w: %s", domain.ErrInvalidCSV, msg)}
+}
@@ -1,13 +1,43 @@
 package httpapi
 
-import "subscription_service/internal/domain"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
 
+	"subscription_service/internal/domain"
+)
+
+// TotalResponse carries no total and no currency for a breakdown by currency, whose buckets are
+// each in their own currency.
 type TotalResponse struct {
-	Total int64 `json:"total"`
+	Total    *int64                `json:"total,omitempty"`
+	Currency string                `json:"currency,omitempty"`
+	GroupBy  string                `json:"group_by,omitempty"`
+	Buckets  []TotalBucketResponse `json:"buckets,omitempty"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+type TotalBucketResponse struct {
+	Key    string `json:"key"`
+	Amount int64  `json:"amount"`
+	Count  int64  `json:"count"`
+}
+
+type TimeseriesResponse struct {
+	Currency string                    `json:"currency"`
+	Points   []TimeseriesPointResponse `json:"points"`
+}
+
+type TimeseriesPointResponse struct {
+	Month   string `json:"month"`
+	Amount  int64  `json:"amount"`
+	Active  int64  `json:"active"`
+	Started int64  `json:"started"`
+	Ended   int64  `json:"ended"`
 }
 
 type IDResponse struct {
@@ -19,43 +49,194 @@ type StatusResponse struct {
 }
 
 type SubscriptionRequest struct {
-	ServiceName string  `json:"service_name"`
-	Price       int     `json:"price"`
-	UserID      string  `json:"user_id"`
-	StartDate   string  `json:"start_date"`
-	EndDate     *string `json:"end_date,omitempty"`
+	ServiceName   string `json:"service_name"`
+	Price         int    `json:"price"`
+	Currency      string `json:"currency,omitempty"`
+	BillingPeriod string `json:"billing_period,omitempty" enums:"weekly,monthly,quarterly,yearly"`
+	UserID        string `json:"user_id"`
+	StartDate     string `json:"start_date" example:"2025-07-15"`
+	// EndDate is the last active day; MM-YYYY stands for the last day of that month.
+	EndDate *string `json:"end_date,omitempty" example:"09-2025"`
+	// TrialEnd is the last day of a free trial, written like EndDate. Price may be 0 for a trial.
+	TrialEnd *string `json:"trial_end,omitempty" example:"07-2025"`
+}
+
+// SubscriptionPatchRequest documents a merge patch (RFC 7396): only the fields present are changed,
+// and null clears end_date or trial_end.
+type SubscriptionPatchRequest struct {
+	ServiceName   *string `json:"service_name,omitempty"`
+	Price         *int    `json:"price,omitempty"`
+	Currency      *string `json:"currency,omitempty"`
+	BillingPeriod *string `json:"billing_period,omitempty" enums:"weekly,monthly,quarterly,yearly"`
+	UserID        *string `json:"user_id,omitempty"`
+	StartDate     *string `json:"start_date,omitempty" example:"2025-07-15"`
+	EndDate       *string `json:"end_date,omitempty" example:"09-2025" extensions:"x-nullable"`
+	TrialEnd      *string `json:"trial_end,omitempty" example:"07-2025" extensions:"x-nullable"`
 }
 
 type SubscriptionResponse struct {
-	ID          string  `json:"id"`
-	ServiceName string  `json:"service_name"`
-	Price       int     `json:"price"`
-	UserID      string  `json:"user_id"`
-	StartDate   string  `json:"start_date"`
-	EndDate     *string `json:"end_date,omitempty"`
+	ID            string     `json:"id"`
+	ServiceName   string     `json:"service_name"`
+	Price         int        `json:"price"`
+	Currency      string     `json:"currency"`
+	BillingPeriod string     `json:"billing_period"`
+	UserID        string     `json:"user_id"`
+	StartDate     string     `json:"start_date"`
+	EndDate       *string    `json:"end_date,omitempty"`
+	TrialEnd      *string    `json:"trial_end,omitempty"`
+	Status        string     `json:"status" enums:"active,cancelled,expired,paused"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason  string     `json:"cancel_reason,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	// Pauses is only returned by GET /subscriptions/{id}.
+	Pauses []PauseResponse `json:"pauses,omitempty"`
+}
+
+type PauseResponse struct {
+	PausedFrom  string  `json:"paused_from"`
+	ResumedFrom *string `json:"resumed_from,omitempty"`
+}
+
+type PauseRequest struct {
+	// From is the first paused day; MM-YYYY stands for the first day of the month.
+	From string `json:"from" example:"06-2025"`
+}
+
+type CancelRequest struct {
+	// EffectiveMonth is the last month of the subscription.
+	EffectiveMonth string `json:"effective_month" example:"09-2025"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+func (dto *CancelRequest) toDomain() domain.Cancellation {
+	return domain.Cancellation{EffectiveMonth: dto.EffectiveMonth, Reason: dto.Reason}
+}
+
+type ResumeRequest struct {
+	// From is the first day charged again; MM-YYYY stands for the first day of the month.
+	From string `json:"from" example:"09-2025"`
+}
+
+type SubscriptionListResponse struct {
+	Items      []SubscriptionResponse `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
 }
 
 func (dto *SubscriptionRequest) toDomain() domain.Subscription {
 	return domain.Subscription{
-		ServiceName: dto.ServiceName,
-		Price:       dto.Price,
-		UserID:      dto.UserID,
-		StartDate:   dto.StartDate,
-		EndDate:     dto.EndDate,
+		ServiceName:   dto.ServiceName,
+		Price:         dto.Price,
+		Currency:      dto.Currency,
+		BillingPeriod: domain.BillingPeriod(dto.BillingPeriod),
+		UserID:        dto.UserID,
+		StartDate:     dto.StartDate,
+		EndDate:       dto.EndDate,
+		TrialEnd:      dto.TrialEnd,
 	}
 }
 
+// decodeSubscriptionPatch reads a merge patch. A null removes a member, which for a required
+// field leaves it empty and for currency and billing_period restores the default.
+func decodeSubscriptionPatch(body io.Reader) (domain.SubscriptionPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&fields); err != nil || fields == nil {
+		return domain.SubscriptionPatch{}, ErrInvalidJSON
+	}
+
+	var patch domain.SubscriptionPatch
+	var billingPeriod *string
+	err := errors.Join(
+		patchField(fields, "service_name", &patch.ServiceName),
+		patchField(fields, "price", &patch.Price),
+		patchField(fields, "currency", &patch.Currency),
+		patchField(fields, "billing_period", &billingPeriod),
+		patchField(fields, "user_id", &patch.UserID),
+		patchField(fields, "start_date", &patch.StartDate),
+		patchNullableField(fields, "end_date", &patch.EndDate),
+		patchNullableField(fields, "trial_end", &patch.TrialEnd),
+	)
+	if err != nil {
+		return domain.SubscriptionPatch{}, ErrInvalidJSON
+	}
+
+	if billingPeriod != nil {
+		period := domain.BillingPeriod(*billingPeriod)
+		patch.BillingPeriod = &period
+	}
+
+	return patch, nil
+}
+
+// patchField sets dst when the patch has the member; null stands for the zero value.
+func patchField[T any](fields map[string]json.RawMessage, name string, dst **T) error {
+	raw, ok := fields[name]
+	if !ok {
+		return nil
+	}
+
+	value := new(T)
+	if !isJSONNull(raw) {
+		if err := json.Unmarshal(raw, value); err != nil {
+			return err
+		}
+	}
+	*dst = value
+	return nil
+}
+
+// patchNullableField sets dst when the patch has the member; null is kept as a nil value.
+func patchNullableField[T any](fields map[string]json.RawMessage, name string, dst ***T) error {
+	raw, ok := fields[name]
+	if !ok {
+		return nil
+	}
+
+	var value *T
+	if !isJSONNull(raw) {
+		value = new(T)
+		if err := json.Unmarshal(raw, value); err != nil {
+			return err
+		}
+	}
+	*dst = &value
+	return nil
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "null"
+}
+
 func fromDomain(sub domain.Subscription) SubscriptionResponse {
 	return SubscriptionResponse{
-		ID:          sub.ID,
-		ServiceName: sub.ServiceName,
-		Price:       sub.Price,
-		UserID:      sub.UserID,
-		StartDate:   sub.StartDate,
-		EndDate:     sub.EndDate,
+		ID:            sub.ID,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		Currency:      sub.Currency,
+		BillingPeriod: string(sub.BillingPeriod),
+		UserID:        sub.UserID,
+		StartDate:     sub.StartDate,
+		EndDate:       sub.EndDate,
+		TrialEnd:      sub.TrialEnd,
+		Status:        string(sub.Status),
+		CancelledAt:   sub.CancelledAt,
+		CancelReason:  sub.CancelReason,
+		DeletedAt:     sub.DeletedAt,
+		Pauses:        fromDomainPauses(sub.Pauses),
 	}
 }
 
+func fromDomainPauses(pauses []domain.Pause) []PauseResponse {
+	if len(pauses) == 0 {
+		return nil
+	}
+
+	result := make([]PauseResponse, len(pauses))
+	for i, pause := range pauses {
+		result[i] = PauseResponse{PausedFrom: pause.PausedFrom, ResumedFrom: pause.ResumedFrom}
+	}
+	return result
+}
+
 func fromDomainList(items []domain.Subscription) []SubscriptionResponse {
 	result := make([]SubscriptionResponse, len(items))
 	for i, sub := range items {
@@ -63,3 +244,309 @@ func fromDomainList(items []domain.Subscription) []SubscriptionResponse {
 	}
 	return result
 }
+
+func fromDomainPage(page domain.SubscriptionPage) SubscriptionListResponse {
+	return SubscriptionListResponse{
+		Items:      fromDomainList(page.Items),
+		NextCursor: page.NextCursor,
+	}
+}
+
+func fromDomainBuckets(currency string, groupBy string, buckets []domain.TotalBucket) TotalResponse {
+	resp := TotalResponse{Currency: currency, GroupBy: groupBy, Buckets: make([]TotalBucketResponse, len(buckets))}
+	var total int64
+	for i, b := range buckets {
+		total += b.Amount
+		resp.Buckets[i] = TotalBucketResponse{Key: b.Key, Amount: b.Amount, Count: b.Count}
+	}
+	if currency != "" {
+		resp.Total = &total
+	}
+	return resp
+}
+
+func fromDomainTimeseries(currency string, points []domain.TimeseriesPoint) TimeseriesResponse {
+	resp := TimeseriesResponse{Currency: currency, Points: make([]TimeseriesPointResponse, len(points))}
+	for i, p := range points {
+		resp.Points[i] = TimeseriesPointResponse{
+			Month:   p.Month,
+			Amount:  p.Amount,
+			Active:  p.Active,
+			Started: p.Started,
+			Ended:   p.Ended,
+		}
+	}
+	return resp
+}
+
+type ExchangeRateRequest struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+type ExchangeRateResponse struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (dto *ExchangeRateRequest) toDomain() domain.ExchangeRate {
+	return domain.ExchangeRate{
+		Base:  dto.Base,
+		Quote: dto.Quote,
+		Rate:  dto.Rate,
+	}
+}
+
+func fromDomainRate(rate domain.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		Base:      rate.Base,
+		Quote:     rate.Quote,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func fromDomainRates(rates []domain.ExchangeRate) []ExchangeRateResponse {
+	result := make([]ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		result[i] = fromDomainRate(rate)
+	}
+	return result
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name"`
+	// UserID is the user the key acts for; keys without one need the admin permission to see subscriptions.
+	UserID    string     `json:"user_id,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty" example:"admin"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	UserID     string     `json:"user_id,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyResponse is the only response that carries the secret of a key.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Secret string `json:"secret"`
+}
+
+func (dto *APIKeyRequest) toDomain() domain.APIKey {
+	return domain.APIKey{
+		Name:      dto.Name,
+		UserID:    dto.UserID,
+		Scopes:    dto.Scopes,
+		ExpiresAt: dto.ExpiresAt,
+	}
+}
+
+func fromDomainAPIKey(key domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		UserID:     key.UserID,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func fromDomainAPIKeys(keys []domain.APIKey) []APIKeyResponse {
+	result := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		result[i] = fromDomainAPIKey(key)
+	}
+	return result
+}
+
+type PriceChangeRequest struct {
+	Price int `json:"price"`
+	// EffectiveFrom is the first day charged at the new price; MM-YYYY stands for the first day of the month.
+	EffectiveFrom string `json:"effective_from" example:"09-2025"`
+}
+
+type PriceChangeResponse struct {
+	Price         int    `json:"price"`
+	EffectiveFrom string `json:"effective_from"`
+}
+
+func (dto *PriceChangeRequest) toDomain(subscriptionID string) domain.PriceChange {
+	return domain.PriceChange{
+		SubscriptionID: subscriptionID,
+		Price:          dto.Price,
+		EffectiveFrom:  dto.EffectiveFrom,
+	}
+}
+
+func fromDomainPriceChanges(changes []domain.PriceChange) []PriceChangeResponse {
+	result := make([]PriceChangeResponse, len(changes))
+	for i, change := range changes {
+		result[i] = PriceChangeResponse{Price: change.Price, EffectiveFrom: change.EffectiveFrom}
+	}
+	return result
+}
+
+type EventResponse struct {
+	ID        int64           `json:"id"`
+	Action    string          `json:"action" example:"update"`
+	Before    json.RawMessage `json:"before" swaggertype:"object"`
+	After     json.RawMessage `json:"after" swaggertype:"object"`
+	Actor     string          `json:"actor" example:"system"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func fromDomainEvents(events []domain.SubscriptionEvent) []EventResponse {
+	result := make([]EventResponse, len(events))
+	for i, event := range events {
+		result[i] = EventResponse{
+			ID:        event.ID,
+			Action:    string(event.Action),
+			Before:    nullJSON(event.Before),
+			After:     nullJSON(event.After),
+			Actor:     event.Actor,
+			RequestID: event.RequestID,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	return result
+}
+
+// nullJSON keeps a missing snapshot as an explicit null; an empty json.RawMessage does not marshal.
+func nullJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
+
+type BatchRequest struct {
+	// Mode is atomic (default) to apply all operations or none, or best_effort to apply each on its own.
+	Mode       string                  `json:"mode,omitempty" enums:"atomic,best_effort"`
+	Operations []BatchOperationRequest `json:"operations"`
+}
+
+type BatchOperationRequest struct {
+	Op string `json:"op" enums:"create,update,delete"`
+	// ID is the subscription to update or delete.
+	ID string `json:"id,omitempty"`
+	// Version makes an update or delete conditional, like the ETag in If-Match.
+	Version      int64                `json:"version,omitempty"`
+	Subscription *SubscriptionRequest `json:"subscription,omitempty"`
+}
+
+type BatchResponse struct {
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BatchResultResponse `json:"results"`
+}
+
+type BatchResultResponse struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status" enums:"ok,failed,rolled_back,skipped"`
+	// Code is the HTTP status the operation would have got on its own endpoint. Operations that were
+	// rolled back or skipped have none.
+	Code  int    `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Errors lists the invalid fields of a failed operation, such as operations[2].subscription.price.
+	Errors []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+func (dto *BatchRequest) toDomain() (domain.BatchMode, []domain.BatchOperation) {
+	ops := make([]domain.BatchOperation, len(dto.Operations))
+	for i, op := range dto.Operations {
+		ops[i] = domain.BatchOperation{Action: domain.BatchAction(op.Op), ID: op.ID, Version: op.Version}
+		if op.Subscription != nil {
+			ops[i].Subscription = op.Subscription.toDomain()
+		}
+	}
+	return domain.BatchMode(dto.Mode), ops
+}
+
+// batchFieldPath places a field of a batch operation: op and id belong to the operation itself,
+// everything else to its subscription.
+func batchFieldPath(field string) string {
+	switch field {
+	case "op", "id", "version":
+		return field
+	}
+	return "subscription." + field
+}
+
+func fromDomainBatchResults(results []domain.BatchResult) BatchResponse {
+	resp := BatchResponse{Results: make([]BatchResultResponse, len(results))}
+	for i, result := range results {
+		item := BatchResultResponse{Index: i, Op: string(result.Action), ID: result.ID, Status: string(result.Status)}
+		switch {
+		case result.Status != domain.BatchStatusOK:
+		case result.Action == domain.BatchCreate:
+			item.Code = http.StatusCreated
+		default:
+			item.Code = http.StatusOK
+		}
+		if result.Err != nil {
+			code, public := classifyError(result.Err)
+			item.Code = code
+			item.Error = public.Error()
+			item.Errors = fieldErrors(public, fmt.Sprintf("operations[%d]", i), batchFieldPath)
+		}
+
+		if result.Status == domain.BatchStatusOK {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+		resp.Results[i] = item
+	}
+	return resp
+}
+
+type ImportResponse struct {
+	DryRun   bool `json:"dry_run"`
+	Rows     int  `json:"rows"`
+	Imported int  `json:"imported"`
+	Failed   int  `json:"failed"`
+	// Errors lists the first 1000 failed rows.
+	Errors []ImportErrorResponse `json:"errors"`
+}
+
+type ImportErrorResponse struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+	// Errors lists the invalid columns of the row, such as lines[7].price.
+	Errors []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+func fromDomainImportReport(report domain.ImportReport) ImportResponse {
+	resp := ImportResponse{
+		DryRun:   report.DryRun,
+		Rows:     report.Rows,
+		Imported: report.Imported,
+		Failed:   report.Failed,
+		Errors:   make([]ImportErrorResponse, len(report.Errors)),
+	}
+	for i, rowErr := range report.Errors {
+		_, public := classifyError(rowErr.Err)
+		resp.Errors[i] = ImportErrorResponse{
+			Line:   rowErr.Line,
+			Error:  public.Error(),
+			Errors: fieldErrors(public, fmt.Sprintf("lines[%d]", rowErr.Line), nil),
+		}
+	}
+	return resp
+}
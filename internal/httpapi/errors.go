@@ -4,5 +4,10 @@ import "errors"
 
 var (
 	ErrInvalidJSON               = errors.New("invalid json")
+	ErrUnsupportedMediaType      = errors.New("unsupported media type")
 	ErrStatusInternalServerError = errors.New("internal server error")
+	ErrRouteNotFound             = errors.New("route not found")
+	ErrMethodNotAllowed          = errors.New("method not allowed")
+	ErrMissingToken              = errors.New("missing bearer token")
+	ErrInvalidToken              = errors.New("invalid bearer token")
 )
@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"subscription_service/internal/domain"
+)
+
+// formatETag renders a strong entity tag for a subscription version and its encoded body. The body
+// hash makes the tag change with fields computed at read time, such as the status, which can
+// change without a write.
+func formatETag(version int64, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// parseIfMatch returns the version a write is conditional on, or 0 without an If-Match header or
+// for "*". Only a single strong tag can match; anything else fails the precondition. The body hash
+// of a tag is ignored: a write only conflicts with other writes, which change the version.
+func parseIfMatch(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	value, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return 0, domain.ErrPreconditionFailed
+	}
+	value, ok = strings.CutSuffix(value, `"`)
+	if !ok {
+		return 0, domain.ErrPreconditionFailed
+	}
+
+	value, _, _ = strings.Cut(value, "-")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, domain.ErrPreconditionFailed
+	}
+	return version, nil
+}
+
+// matchesNoneMatch reports whether an If-None-Match header matches etag, using weak comparison.
+func matchesNoneMatch(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"subscription_service/pkg/logger"
+)
+
+type ExchangeRateHandler struct {
+	log     logger.Logger
+	service exchangeRateService
+}
+
+func NewExchangeRateHandler(log logger.Logger, service exchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{log: log, service: service}
+}
+
+// SetExchangeRate godoc
+// @Summary Set exchange rate
+// @Description Create or replace the rate converting base into quote (1 base = rate quote).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} ExchangeRateResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/exchange-rates [put]
+func (h *ExchangeRateHandler) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	var reqDTO ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	rate, err := h.service.Set(r.Context(), reqDTO.toDomain())
+	if err != nil {
+		handleError(h.log, w, r, err, "set exchange rate")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainRate(rate)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Tags admin
+// @Produce json
+// @Success 200 {array} ExchangeRateResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/exchange-rates [get]
+func (h *ExchangeRateHandler) ListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.List(r.Context())
+	if err != nil {
+		handleError(h.log, w, r, err, "list exchange rates")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainRates(rates)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete exchange rate
+// @Tags admin
+// @Produce json
+// @Param base path string true "Base currency (ISO 4217)"
+// @Param quote path string true "Quote currency (ISO 4217)"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /admin/exchange-rates/{base}/{quote} [delete]
+func (h *ExchangeRateHandler) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), chi.URLParam(r, "base"), chi.URLParam(r, "quote")); err != nil {
+		handleError(h.log, w, r, err, "delete exchange rate")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "ok"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
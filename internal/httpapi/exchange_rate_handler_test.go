@@ -0,0 +1,72 @@
+package httpapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	"subscription_service/internal/httpapi"
+	"subscription_service/pkg/logger"
+)
+
+func newRatesRouter(ctrl *gomock.Controller, rates *MockexchangeRateService) http.Handler {
+	log := logger.NewNoop()
+	return authenticated(httpapi.NewHandler(
+		log,
+		httpapi.NewSubscriptionHandler(log, NewMocksubscriptionService(ctrl)),
+		httpapi.NewExchangeRateHandler(log, rates),
+		httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+		httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+		newTestAuth(),
+		httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+	), domain.RoleAdmin)
+}
+
+func TestSetExchangeRate_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rates := NewMockexchangeRateService(ctrl)
+	rates.EXPECT().Set(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+			require.Equal(t, domain.ExchangeRate{Base: "EUR", Quote: "RUB", Rate: "98.5"}, rate)
+			rate.UpdatedAt = time.Now()
+			return rate, nil
+		})
+	h := newRatesRouter(ctrl, rates)
+
+	body := []byte(`{"base":"EUR","quote":"RUB","rate":"98.5"}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/exchange-rates/", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.ExchangeRateResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "98.5", resp.Rate)
+}
+
+func TestDeleteExchangeRate_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rates := NewMockexchangeRateService(ctrl)
+	rates.EXPECT().Delete(gomock.Any(), "EUR", "USD").Return(domain.ErrExchangeRateNotFound)
+	h := newRatesRouter(ctrl, rates)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/exchange-rates/EUR/USD", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,169 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"subscription_service/internal/domain"
+	"subscription_service/pkg/xlsx"
+)
+
+// exportColumns are the columns of CSV and XLSX exports. The import columns are among them.
+var exportColumns = []string{
+	"id", "service_name", "price", "currency", "billing_period", "user_id", "start_date", "end_date", "trial_end",
+	"status", "cancelled_at", "cancel_reason",
+}
+
+// subscriptionEncoder writes an export one subscription at a time.
+type subscriptionEncoder interface {
+	Encode(sub domain.Subscription) error
+	// Close writes whatever the format needs after the last subscription.
+	Close() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newEncoder  func(w io.Writer) (subscriptionEncoder, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv": {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		newEncoder:  newCSVEncoder,
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		extension:   "ndjson",
+		newEncoder: func(w io.Writer) (subscriptionEncoder, error) {
+			return ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+		},
+	},
+	"xlsx": {
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		extension:   "xlsx",
+		newEncoder:  newXLSXEncoder,
+	},
+}
+
+// attachment is the Content-Disposition of an export made on the given day.
+func (f exportFormat) attachment(now time.Time) string {
+	filename := "subscriptions-" + now.Format(time.DateOnly) + "." + f.extension
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
+
+// exportRecord lays a subscription out in exportColumns order. Missing values are nil.
+func exportRecord(sub domain.Subscription) []any {
+	optional := func(value *string) any {
+		if value == nil {
+			return nil
+		}
+		return *value
+	}
+
+	var cancelledAt any
+	if sub.CancelledAt != nil {
+		cancelledAt = sub.CancelledAt.Format(time.RFC3339)
+	}
+
+	var cancelReason any
+	if sub.CancelReason != "" {
+		cancelReason = sub.CancelReason
+	}
+
+	return []any{
+		sub.ID, sub.ServiceName, sub.Price, sub.Currency, string(sub.BillingPeriod), sub.UserID, sub.StartDate,
+		optional(sub.EndDate), optional(sub.TrialEnd), string(sub.Status), cancelledAt, cancelReason,
+	}
+}
+
+// csvFormulaPrefixes are the characters a spreadsheet reads as the start of a formula.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvCell neutralises a text cell a spreadsheet would evaluate as a formula by prefixing it with
+// an apostrophe. The CSV import strips it again (see csvUnescape).
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVEncoder(w io.Writer) (subscriptionEncoder, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return &csvEncoder{w: cw, record: make([]string, len(exportColumns))}, nil
+}
+
+func (e *csvEncoder) Encode(sub domain.Subscription) error {
+	for i, value := range exportRecord(sub) {
+		switch v := value.(type) {
+		case nil:
+			e.record[i] = ""
+		case int:
+			e.record[i] = strconv.Itoa(v)
+		case string:
+			e.record[i] = csvCell(v)
+		}
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e ndjsonEncoder) Encode(sub domain.Subscription) error {
+	return e.enc.Encode(fromDomain(sub))
+}
+
+func (e ndjsonEncoder) Close() error {
+	return nil
+}
+
+type xlsxEncoder struct {
+	w *xlsx.Writer
+}
+
+func newXLSXEncoder(w io.Writer) (subscriptionEncoder, error) {
+	xw, err := xlsx.NewWriter(w, "Subscriptions")
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]any, len(exportColumns))
+	for i, column := range exportColumns {
+		header[i] = column
+	}
+	if err := xw.WriteRow(header...); err != nil {
+		return nil, err
+	}
+
+	return xlsxEncoder{w: xw}, nil
+}
+
+func (e xlsxEncoder) Encode(sub domain.Subscription) error {
+	return e.w.WriteRow(exportRecord(sub)...)
+}
+
+func (e xlsxEncoder) Close() error {
+	return e.w.Close()
+}
@@ -3,6 +3,7 @@ package httpapi
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -21,25 +22,32 @@ func NewSubscriptionHandler(log logger.Logger, service subscriptionService) *Sub
 
 // CreateSubscription godoc
 // @Summary Create subscription
-// @Description Create a new subscription record.
+// @Description Create a new subscription record. Dates are YYYY-MM-DD or MM-YYYY for a whole month.
+// @Description With an Idempotency-Key a retry with the same body replays the original response.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key that makes the request safe to retry"
 // @Param request body SubscriptionRequest true "Subscription data"
 // @Success 201 {object} IDResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 422 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions [post]
 func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	var reqDTO SubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
-		newErrorResponse(w, http.StatusBadRequest, ErrInvalidJSON)
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
 		return
 	}
 
 	id, err := h.service.Create(r.Context(), reqDTO.toDomain())
 	if err != nil {
-		h.handleError(w, err, "create subscription")
+		handleError(h.log, w, r, err, "create subscription")
 		return
 	}
 
@@ -48,48 +56,291 @@ func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.
 	}
 }
 
+// BatchSubscriptions godoc
+// @Summary Create, update and delete subscriptions in bulk
+// @Description Applies up to 1000 operations in order, each validated like its single-item endpoint.
+// @Description In atomic mode the first failure rolls back the operations before it and skips the rest;
+// @Description in best_effort mode every operation is applied on its own. The response reports each operation.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key that makes the request safe to retry"
+// @Param request body BatchRequest true "Operations"
+// @Success 200 {object} BatchResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/batch [post]
+func (h *SubscriptionHandler) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var reqDTO BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	mode, ops := reqDTO.toDomain()
+	results, err := h.service.Batch(r.Context(), mode, ops)
+	if err != nil {
+		handleError(h.log, w, r, err, "batch subscriptions")
+		return
+	}
+
+	for i, result := range results {
+		if code, _ := classifyError(result.Err); result.Err != nil && code == http.StatusInternalServerError {
+			h.log.Error("operation failed", "operation", "batch subscriptions", "index", i, "error", result.Err)
+		}
+	}
+
+	resp := fromDomainBatchResults(results)
+	if err := writeJSON(w, http.StatusOK, resp); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ImportSubscriptions godoc
+// @Summary Import subscriptions from CSV
+// @Description The first line names the columns: service_name, price, user_id and start_date are required,
+// @Description currency, billing_period, end_date and trial_end are optional. Every row is validated like a create.
+// @Description The file is read and validated first, then imported in one transaction, and only if all rows are valid;
+// @Description with dry_run=true it is only checked. Files over 10 MiB or 10000 rows are rejected with 413.
+// @Description The report lists failed rows by their line in the file.
+// @Tags subscriptions
+// @Accept text/csv
+// @Produce json
+// @Param dry_run query bool false "Only validate the file"
+// @Param file body string true "CSV file"
+// @Success 200 {object} ImportResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 413 {object} ProblemResponse
+// @Failure 415 {object} ProblemResponse
+// @Failure 422 {object} ImportResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/import [post]
+func (h *SubscriptionHandler) ImportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if !isCSV(r.Header.Get("Content-Type")) {
+		newErrorResponse(w, r, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType)
+		return
+	}
+
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "import subscriptions")
+		return
+	}
+
+	rows, err := readSubscriptionsCSV(http.MaxBytesReader(w, r.Body, domain.MaxImportBytes))
+	if err != nil {
+		handleError(h.log, w, r, err, "import subscriptions")
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		handleError(h.log, w, r, err, "import subscriptions")
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+
+	if err := writeJSON(w, status, fromDomainImportReport(report)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
 // GetSubscription godoc
 // @Summary Get subscription by ID
+// @Description The ETag header carries the subscription version and a hash of the body; send it back in If-Match to make a write conditional.
 // @Tags subscriptions
 // @Produce json
 // @Param id path string true "Subscription ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} SubscriptionResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 304 "Not modified"
+// @Header 200,304 {string} ETag "Subscription version and body hash"
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions/{id} [get]
 func (h *SubscriptionHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	sub, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		h.handleError(w, err, "get subscription")
+		handleError(h.log, w, r, err, "get subscription")
 		return
 	}
 
-	if err := writeJSON(w, http.StatusOK, fromDomain(sub)); err != nil {
+	resp := fromDomain(sub)
+	body, err := json.Marshal(resp)
+	if err != nil {
+		handleError(h.log, w, r, err, "encode subscription")
+		return
+	}
+
+	etag := formatETag(sub.Version, body)
+	w.Header().Set("ETag", etag)
+	if matchesNoneMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, resp); err != nil {
 		h.log.Error("failed to write response", "error", err)
 	}
 }
 
 // ListSubscriptions godoc
 // @Summary List subscriptions
-// @Description List subscriptions with optional filters.
+// @Description List subscriptions with optional filters. Results are paginated with opaque keyset cursors.
 // @Tags subscriptions
 // @Produce json
 // @Param user_id query string false "User ID (UUID)"
 // @Param service_name query string false "Service name"
-// @Success 200 {array} SubscriptionResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Param search query string false "Case-insensitive substring of the service name"
+// @Param min_price query int false "Minimum price"
+// @Param max_price query int false "Maximum price"
+// @Param active_in query string false "Active in month (MM-YYYY)"
+// @Param start_from query string false "Start date lower bound (MM-YYYY)"
+// @Param start_to query string false "Start date upper bound (MM-YYYY)"
+// @Param end_from query string false "End date lower bound (MM-YYYY)"
+// @Param end_to query string false "End date upper bound (MM-YYYY)"
+// @Param open_ended query bool false "Only subscriptions without an end date"
+// @Param status query string false "Status: active, cancelled, expired or paused"
+// @Param trial_ends_within query int false "Only subscriptions whose trial ends within this many days from today"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor from the previous page's next_cursor"
+// @Param sort query string false "Sort field: created_at, price, start_date or service_name; prefix with - for descending (default -created_at)"
+// @Success 200 {object} SubscriptionListResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions [get]
 func (h *SubscriptionHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
-	items, err := h.service.List(r.Context(), r.URL.Query().Get("user_id"), r.URL.Query().Get("service_name"))
+	filter, err := parseListFilter(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "list subscriptions")
+		return
+	}
+
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "list subscriptions")
+		return
+	}
+
+	result, err := h.service.List(r.Context(), filter, page)
+	if err != nil {
+		handleError(h.log, w, r, err, "list subscriptions")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainPage(result)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ExportSubscriptions godoc
+// @Summary Export subscriptions
+// @Description Streams every subscription matching the list filters, oldest first, as CSV, NDJSON or XLSX.
+// @Description CSV and XLSX files start with a header row; NDJSON has one subscription object per line.
+// @Tags subscriptions
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format: csv, ndjson or xlsx (default csv)"
+// @Param user_id query string false "User ID (UUID)"
+// @Param service_name query string false "Service name"
+// @Param search query string false "Case-insensitive substring of the service name"
+// @Param min_price query int false "Minimum price"
+// @Param max_price query int false "Maximum price"
+// @Param active_in query string false "Active in month (MM-YYYY)"
+// @Param start_from query string false "Start date lower bound (MM-YYYY)"
+// @Param start_to query string false "Start date upper bound (MM-YYYY)"
+// @Param end_from query string false "End date lower bound (MM-YYYY)"
+// @Param end_to query string false "End date upper bound (MM-YYYY)"
+// @Param open_ended query bool false "Only subscriptions without an end date"
+// @Param status query string false "Status: active, cancelled, expired or paused"
+// @Param trial_ends_within query int false "Only subscriptions whose trial ends within this many days from today"
+// @Success 200 {file} file
+// @Header 200 {string} Content-Disposition "attachment; filename=subscriptions-YYYY-MM-DD.<format>"
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/export [get]
+func (h *SubscriptionHandler) ExportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	format, err := parseExportFormat(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "export subscriptions")
+		return
+	}
+
+	filter, err := parseListFilter(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "export subscriptions")
+		return
+	}
+
+	subs, err := h.service.Export(r.Context(), filter)
 	if err != nil {
-		h.handleError(w, err, "list subscriptions")
+		handleError(h.log, w, r, err, "export subscriptions")
 		return
 	}
 
-	if err := writeJSON(w, http.StatusOK, fromDomainList(items)); err != nil {
+	// The response is only started with the first subscription, so that an error before it
+	// still gets a proper status. Later errors can only abort the response.
+	var enc subscriptionEncoder
+	start := func() (err error) {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", format.attachment(time.Now()))
+		w.WriteHeader(http.StatusOK)
+
+		enc, err = format.newEncoder(w)
+		return err
+	}
+
+	for sub, err := range subs {
+		if err != nil {
+			if enc == nil {
+				handleError(h.log, w, r, err, "export subscriptions")
+				return
+			}
+			h.log.Error("failed to export subscriptions", "error", err)
+			panic(http.ErrAbortHandler)
+		}
+
+		if enc == nil {
+			if err := start(); err != nil {
+				h.log.Error("failed to write response", "error", err)
+				return
+			}
+		}
+		if err := enc.Encode(sub); err != nil {
+			h.log.Error("failed to write response", "error", err)
+			return
+		}
+	}
+
+	if enc == nil {
+		if err := start(); err != nil {
+			h.log.Error("failed to write response", "error", err)
+			return
+		}
+	}
+	if err := enc.Close(); err != nil {
 		h.log.Error("failed to write response", "error", err)
 	}
 }
@@ -100,25 +351,90 @@ func (h *SubscriptionHandler) ListSubscriptions(w http.ResponseWriter, r *http.R
 // @Accept json
 // @Produce json
 // @Param id path string true "Subscription ID"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Param request body SubscriptionRequest true "Subscription data"
 // @Success 200 {string} string "updated successfully"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 412 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions/{id} [put]
 func (h *SubscriptionHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	version, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		handleError(h.log, w, r, err, "update subscription")
+		return
+	}
+
 	var reqDTO SubscriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
-		newErrorResponse(w, http.StatusBadRequest, ErrInvalidJSON)
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
 		return
 	}
 
 	sub := reqDTO.toDomain()
 	sub.ID = id
+	sub.Version = version
 
 	if err := h.service.Update(r.Context(), sub); err != nil {
-		h.handleError(w, err, "update subscription")
+		handleError(h.log, w, r, err, "update subscription")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "updated successfully"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// PatchSubscription godoc
+// @Summary Partially update subscription
+// @Description JSON merge patch (RFC 7396): only the fields present in the body are changed and null clears
+// @Description end_date or trial_end. The merged subscription is validated like a full update.
+// @Tags subscriptions
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param request body SubscriptionPatchRequest true "Fields to change"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 412 {object} ProblemResponse
+// @Failure 415 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id} [patch]
+func (h *SubscriptionHandler) PatchSubscription(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if !isMergePatch(r.Header.Get("Content-Type")) {
+		newErrorResponse(w, r, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType)
+		return
+	}
+
+	version, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		handleError(h.log, w, r, err, "patch subscription")
+		return
+	}
+
+	patch, err := decodeSubscriptionPatch(r.Body)
+	if err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	patch.Version = version
+
+	if err := h.service.Patch(r.Context(), id, patch); err != nil {
+		handleError(h.log, w, r, err, "patch subscription")
 		return
 	}
 
@@ -129,18 +445,30 @@ func (h *SubscriptionHandler) UpdateSubscription(w http.ResponseWriter, r *http.
 
 // DeleteSubscription godoc
 // @Summary Delete subscription
+// @Description Move a subscription to the trash. It can be restored until the retention period ends.
 // @Tags subscriptions
 // @Produce json
 // @Param id path string true "Subscription ID"
+// @Param If-Match header string false "ETag the deletion is conditional on"
 // @Success 200 {object} StatusResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 412 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions/{id} [delete]
 func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.service.Delete(r.Context(), id); err != nil {
-		h.handleError(w, err, "delete subscription")
+	version, err := parseIfMatch(r.Header.Get("If-Match"))
+	if err != nil {
+		handleError(h.log, w, r, err, "delete subscription")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, version); err != nil {
+		handleError(h.log, w, r, err, "delete subscription")
 		return
 	}
 
@@ -149,40 +477,347 @@ func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.
 	}
 }
 
+// ListTrash godoc
+// @Summary List deleted subscriptions
+// @Description Subscriptions in the trash, with the same filters and pagination as the list endpoint. They are purged after the retention period.
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "User ID (UUID)"
+// @Param service_name query string false "Service name"
+// @Param search query string false "Case-insensitive substring of the service name"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor from the previous page's next_cursor"
+// @Param sort query string false "Sort field: created_at, price, start_date or service_name; prefix with - for descending (default -created_at)"
+// @Success 200 {object} SubscriptionListResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/trash [get]
+func (h *SubscriptionHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseListFilter(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "list trash")
+		return
+	}
+
+	page, err := parsePageRequest(r.URL.Query())
+	if err != nil {
+		handleError(h.log, w, r, err, "list trash")
+		return
+	}
+
+	result, err := h.service.ListTrash(r.Context(), filter, page)
+	if err != nil {
+		handleError(h.log, w, r, err, "list trash")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainPage(result)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// RestoreSubscription godoc
+// @Summary Restore subscription
+// @Description Take a deleted subscription out of the trash.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/restore [post]
+func (h *SubscriptionHandler) RestoreSubscription(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if err := h.service.Restore(r.Context(), id); err != nil {
+		handleError(h.log, w, r, err, "restore subscription")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "restored"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// PauseSubscription godoc
+// @Summary Pause subscription
+// @Description Stop charging a subscription from the given day until it is resumed.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body PauseRequest true "First paused day"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/pause [post]
+func (h *SubscriptionHandler) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var reqDTO PauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.Pause(r.Context(), id, reqDTO.From); err != nil {
+		handleError(h.log, w, r, err, "pause subscription")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "paused"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ResumeSubscription godoc
+// @Summary Resume subscription
+// @Description Close the open pause of a subscription; charges start again on the given day.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body ResumeRequest true "First day charged again"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/resume [post]
+func (h *SubscriptionHandler) ResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var reqDTO ResumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.Resume(r.Context(), id, reqDTO.From); err != nil {
+		handleError(h.log, w, r, err, "resume subscription")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "resumed"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// CancelSubscription godoc
+// @Summary Cancel subscription
+// @Description End a subscription with its effective month and record why. Cancelled and expired subscriptions cannot be cancelled.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body CancelRequest true "Cancellation"
+// @Success 200 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 409 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/cancel [post]
+func (h *SubscriptionHandler) CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var reqDTO CancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.Cancel(r.Context(), id, reqDTO.toDomain()); err != nil {
+		handleError(h.log, w, r, err, "cancel subscription")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, StatusResponse{Status: "cancelled"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ChangeSubscriptionPrice godoc
+// @Summary Record a price change
+// @Description Charge a new price from effective_from onwards. Charges before it keep the previous price.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body PriceChangeRequest true "Price change"
+// @Success 201 {object} StatusResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/price-changes [post]
+func (h *SubscriptionHandler) ChangeSubscriptionPrice(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var reqDTO PriceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqDTO); err != nil {
+		newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+		return
+	}
+
+	if err := h.service.ChangePrice(r.Context(), reqDTO.toDomain(id)); err != nil {
+		handleError(h.log, w, r, err, "change subscription price")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusCreated, StatusResponse{Status: "ok"}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// ListSubscriptionPriceChanges godoc
+// @Summary List price changes
+// @Description Price changes of a subscription, oldest first. Before the first change the subscription's own price applies.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} PriceChangeResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/price-changes [get]
+func (h *SubscriptionHandler) ListSubscriptionPriceChanges(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	changes, err := h.service.ListPriceChanges(r.Context(), id)
+	if err != nil {
+		handleError(h.log, w, r, err, "list subscription price changes")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainPriceChanges(changes)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// SubscriptionHistory godoc
+// @Summary Subscription history
+// @Description Audit trail of a subscription, oldest first: every change with the state before and after it,
+// @Description who made it and the request it came from. The history outlives a purge of the subscription.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} EventResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 404 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/{id}/history [get]
+func (h *SubscriptionHandler) SubscriptionHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	events, err := h.service.History(r.Context(), id)
+	if err != nil {
+		handleError(h.log, w, r, err, "get subscription history")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, fromDomainEvents(events)); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
 // TotalSubscriptions godoc
 // @Summary Calculate total subscriptions cost
-// @Description Sum of subscription costs for the specified period with optional filters.
+// @Description Sum of subscription costs for the specified period of at most 120 months, with optional filters.
+// @Description With group_by the total is also broken down into buckets that sum to it.
+// @Description Amounts are converted to the requested currency using the exchange rates table. Without a
+// @Description currency they stay in the one currency the subscriptions are billed in, or are converted to RUB
+// @Description when those differ. group_by=currency keeps each bucket in its own currency and reports no total.
 // @Tags subscriptions
 // @Produce json
 // @Param from query string true "Start period (MM-YYYY)"
 // @Param to query string true "End period (MM-YYYY)"
 // @Param user_id query string false "User ID (UUID)"
 // @Param service_name query string false "Service name"
+// @Param currency query string false "Currency of the result (ISO 4217)"
+// @Param group_by query string false "Breakdown: service_name, user_id, month or currency"
+// @Param proration query string false "none (default) or daily: charge only the active days of a period cut by the end date"
 // @Success 200 {object} TotalResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 422 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /subscriptions/total [get]
 func (h *SubscriptionHandler) TotalSubscriptions(w http.ResponseWriter, r *http.Request) {
-	to := r.URL.Query().Get("to")
-	var endDate *string
-	if to != "" {
-		endDate = &to
+	filter := parsePeriodFilter(r.URL.Query())
+
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		buckets, currency, err := h.service.TotalBreakdown(r.Context(), filter, groupBy)
+		if err != nil {
+			handleError(h.log, w, r, err, "calculate subscriptions breakdown")
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, fromDomainBuckets(currency, groupBy, buckets)); err != nil {
+			h.log.Error("failed to write response", "error", err)
+		}
+		return
 	}
 
-	filter := domain.Subscription{
-		UserID:      r.URL.Query().Get("user_id"),
-		ServiceName: r.URL.Query().Get("service_name"),
-		StartDate:   r.URL.Query().Get("from"),
-		EndDate:     endDate,
+	total, currency, err := h.service.Total(r.Context(), filter)
+	if err != nil {
+		handleError(h.log, w, r, err, "calculate subscriptions total")
+		return
 	}
 
-	total, err := h.service.Total(r.Context(), filter)
+	if err := writeJSON(w, http.StatusOK, TotalResponse{Total: &total, Currency: currency}); err != nil {
+		h.log.Error("failed to write response", "error", err)
+	}
+}
+
+// TimeseriesSubscriptions godoc
+// @Summary Monthly spend timeseries
+// @Description One point per calendar month of the period with spend, active subscriptions, new starts and endings.
+// @Tags subscriptions
+// @Produce json
+// @Param from query string true "Start period (MM-YYYY)"
+// @Param to query string true "End period (MM-YYYY)"
+// @Param user_id query string false "User ID (UUID)"
+// @Param service_name query string false "Service name"
+// @Param currency query string false "Currency of the result (ISO 4217)"
+// @Param proration query string false "none (default) or daily: charge only the active days of a period cut by the end date"
+// @Success 200 {object} TimeseriesResponse
+// @Failure 400 {object} ProblemResponse
+// @Failure 422 {object} ProblemResponse
+// @Failure 403 {object} ProblemResponse
+// @Failure 500 {object} ProblemResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /subscriptions/timeseries [get]
+func (h *SubscriptionHandler) TimeseriesSubscriptions(w http.ResponseWriter, r *http.Request) {
+	filter := parsePeriodFilter(r.URL.Query())
+	points, currency, err := h.service.Timeseries(r.Context(), filter)
 	if err != nil {
-		h.handleError(w, err, "calculate subscriptions total")
+		handleError(h.log, w, r, err, "calculate subscriptions timeseries")
 		return
 	}
 
-	if err := writeJSON(w, http.StatusOK, TotalResponse{Total: total}); err != nil {
+	if err := writeJSON(w, http.StatusOK, fromDomainTimeseries(currency, points)); err != nil {
 		h.log.Error("failed to write response", "error", err)
 	}
 }
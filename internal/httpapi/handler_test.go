@@ -1,12 +1,20 @@
 package httpapi_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -17,6 +25,19 @@ import (
 	"subscription_service/pkg/logger"
 )
 
+func newTestRouter(ctrl *gomock.Controller, svc *MocksubscriptionService) http.Handler {
+	log := logger.NewNoop()
+	return authenticated(httpapi.NewHandler(
+		log,
+		httpapi.NewSubscriptionHandler(log, svc),
+		httpapi.NewExchangeRateHandler(log, NewMockexchangeRateService(ctrl)),
+		httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+		httpapi.NewIdempotencyMiddleware(log, NewMockidempotencyService(ctrl)),
+		newTestAuth(),
+		httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+	))
+}
+
 func TestCreateSubscription_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -29,9 +50,7 @@ func TestCreateSubscription_OK(t *testing.T) {
 			require.Equal(t, 400, sub.Price)
 			return "id-123", nil
 		})
-	log := logger.NewNoop()
-	apiHandler := httpapi.NewSubscriptionHandler(log, svc)
-	h := httpapi.NewHandler(log, apiHandler)
+	h := newTestRouter(ctrl, svc)
 
 	body := []byte(`{"service_name":"Netflix","price":400,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/", bytes.NewReader(body))
@@ -51,9 +70,7 @@ func TestCreateSubscription_InvalidJSON(t *testing.T) {
 	defer ctrl.Finish()
 
 	svc := NewMocksubscriptionService(ctrl)
-	log := logger.NewNoop()
-	apiHandler := httpapi.NewSubscriptionHandler(log, svc)
-	h := httpapi.NewHandler(log, apiHandler)
+	h := newTestRouter(ctrl, svc)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/", bytes.NewBufferString("{"))
 	w := httptest.NewRecorder()
@@ -61,9 +78,47 @@ func TestCreateSubscription_InvalidJSON(t *testing.T) {
 	h.ServeHTTP(w, req)
 
 	require.Equal(t, http.StatusBadRequest, w.Code)
-	var resp map[string]string
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, httpapi.ProblemResponse{
+		Type:      "/problems/bad-request",
+		Title:     "Bad Request",
+		Status:    http.StatusBadRequest,
+		Detail:    httpapi.ErrInvalidJSON.Error(),
+		Instance:  "/api/v1/subscriptions/",
+		RequestID: resp.RequestID,
+	}, resp)
+	require.NotEmpty(t, resp.RequestID)
+}
+
+func TestCreateSubscription_ValidationProblem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var errs domain.FieldErrors
+	errs.Add("price", domain.ErrInvalidPrice)
+	errs.Add("start_date", domain.ErrInvalidStartDate)
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("", errs.Err())
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"service_name":"Netflix","price":-1,"user_id":"` + uuid.NewString() + `","start_date":"2025"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp httpapi.ProblemResponse
 	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
-	require.Equal(t, httpapi.ErrInvalidJSON.Error(), resp["error"])
+	require.Equal(t, "/problems/validation-error", resp.Type)
+	require.Equal(t, "price: invalid price; start_date: invalid start date", resp.Detail)
+	require.Equal(t, []httpapi.FieldErrorResponse{
+		{Field: "price", Code: "invalid_price", Message: domain.ErrInvalidPrice.Error()},
+		{Field: "start_date", Code: "invalid_start_date", Message: domain.ErrInvalidStartDate.Error()},
+	}, resp.Errors)
 }
 
 func TestGetSubscription_NotFound(t *testing.T) {
@@ -73,9 +128,7 @@ func TestGetSubscription_NotFound(t *testing.T) {
 	svc := NewMocksubscriptionService(ctrl)
 	svc.EXPECT().GetByID(gomock.Any(), gomock.Any()).
 		Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
-	log := logger.NewNoop()
-	apiHandler := httpapi.NewSubscriptionHandler(log, svc)
-	h := httpapi.NewHandler(log, apiHandler)
+	h := newTestRouter(ctrl, svc)
 
 	id := uuid.NewString()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
@@ -86,6 +139,122 @@ func TestGetSubscription_NotFound(t *testing.T) {
 	require.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestListSubscriptions_Paginated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().List(gomock.Any(), domain.ListFilter{ServiceName: "Netflix"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error) {
+			require.Equal(t, 2, page.Limit)
+			require.Equal(t, "-price", page.Sort)
+			require.Equal(t, "abc", page.Cursor)
+			return domain.SubscriptionPage{
+				Items:      []domain.Subscription{{ID: "id-1", ServiceName: "Netflix", Price: 500}},
+				NextCursor: "next",
+			}, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/?service_name=Netflix&limit=2&sort=-price&cursor=abc", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.SubscriptionListResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Items, 1)
+	require.Equal(t, "id-1", resp.Items[0].ID)
+	require.Equal(t, "next", resp.NextCursor)
+}
+
+func TestListSubscriptions_InvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/?limit=ten", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "/problems/validation-error", resp.Type)
+	require.Equal(t, []httpapi.FieldErrorResponse{
+		{Field: "limit", Code: "invalid_limit", Message: domain.ErrInvalidLimit.Error()},
+	}, resp.Errors)
+}
+
+func TestListSubscriptions_ReportsEveryInvalidFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/?min_price=low&open_ended=maybe", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "Validation failed", resp.Title)
+	require.Equal(t, http.StatusBadRequest, resp.Status)
+	require.Equal(t, []httpapi.FieldErrorResponse{
+		{Field: "min_price", Code: "invalid_min_price", Message: domain.ErrInvalidMinPrice.Error()},
+		{Field: "open_ended", Code: "invalid_open_ended_flag", Message: domain.ErrInvalidOpenEnded.Error()},
+	}, resp.Errors)
+}
+
+func TestUnknownRoute_Problem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/plans", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "/problems/not-found", resp.Type)
+	require.Equal(t, "/api/v1/plans", resp.Instance)
+}
+
+func TestListSubscriptions_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter domain.ListFilter, _ domain.PageRequest) (domain.SubscriptionPage, error) {
+			require.Equal(t, "flix", filter.Search)
+			require.NotNil(t, filter.MinPrice)
+			require.Equal(t, 100, *filter.MinPrice)
+			require.Nil(t, filter.MaxPrice)
+			require.Equal(t, "07-2025", filter.ActiveIn)
+			require.True(t, filter.OpenEnded)
+			require.Equal(t, domain.StatusCancelled, filter.Status)
+			return domain.SubscriptionPage{}, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/?search=flix&min_price=100&active_in=07-2025&open_ended=true&status=cancelled", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestUpdateSubscription_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -96,9 +265,7 @@ func TestUpdateSubscription_OK(t *testing.T) {
 			require.Equal(t, "Netflix", sub.ServiceName)
 			return nil
 		})
-	log := logger.NewNoop()
-	apiHandler := httpapi.NewSubscriptionHandler(log, svc)
-	h := httpapi.NewHandler(log, apiHandler)
+	h := newTestRouter(ctrl, svc)
 
 	id := uuid.NewString()
 	body := []byte(`{"service_name":"Netflix","price":500,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}`)
@@ -111,29 +278,923 @@ func TestUpdateSubscription_OK(t *testing.T) {
 	require.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestPatchSubscription_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Patch(gomock.Any(), id, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, patch domain.SubscriptionPatch) error {
+			require.NotNil(t, patch.Price)
+			require.Equal(t, 600, *patch.Price)
+			require.NotNil(t, patch.EndDate)
+			require.Nil(t, *patch.EndDate)
+			require.Nil(t, patch.TrialEnd)
+			require.Nil(t, patch.ServiceName)
+			return nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"price":600,"end_date":null}`)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/subscriptions/"+id, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPatchSubscription_BadRequest(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{name: "not an object", contentType: "application/merge-patch+json", body: `[1]`, status: http.StatusBadRequest},
+		{name: "wrong type", contentType: "application/merge-patch+json", body: `{"price":"600"}`, status: http.StatusBadRequest},
+		{name: "media type", contentType: "text/plain", body: `{"price":600}`, status: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/subscriptions/"+uuid.NewString(), bytes.NewReader([]byte(tc.body)))
+			req.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestGetSubscription_ETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	active := domain.Subscription{ID: id, Version: 3, Status: domain.StatusActive}
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().GetByID(gomock.Any(), id).Return(active, nil).Times(3)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `"3-`), etag)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
+	req.Header.Set("If-None-Match", `"2", W/`+etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Equal(t, etag, w.Header().Get("ETag"))
+	require.Zero(t, w.Body.Len())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
+	req.Header.Set("If-None-Match", `"3"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The status moves on with the date alone, without a new version.
+	expired := active
+	expired.Status = domain.StatusExpired
+	svc.EXPECT().GetByID(gomock.Any(), id).Return(expired, nil)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+func TestUpdateSubscription_IfMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.Subscription) error {
+			require.Equal(t, int64(3), sub.Version)
+			return domain.ErrPreconditionFailed
+		})
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"service_name":"Netflix","price":500,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/subscriptions/"+id, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestDeleteSubscription_IfMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		ifMatch string
+		version int64
+	}{
+		{name: "absent", ifMatch: "", version: 0},
+		{name: "any", ifMatch: "*", version: 0},
+		{name: "strong", ifMatch: `"7"`, version: 7},
+		{name: "with body hash", ifMatch: `"7-0123456789abcdef"`, version: 7},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			id := uuid.NewString()
+			svc := NewMocksubscriptionService(ctrl)
+			svc.EXPECT().Delete(gomock.Any(), id, tc.version).Return(nil)
+			h := newTestRouter(ctrl, svc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+id, nil)
+			req.Header.Set("If-Match", tc.ifMatch)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+
+	// A weak or malformed tag can never match.
+	for _, ifMatch := range []string{`W/"7"`, `7`, `"x"`} {
+		ctrl := gomock.NewController(t)
+		h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+		req.Header.Set("If-Match", ifMatch)
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusPreconditionFailed, w.Code, ifMatch)
+	}
+}
+
+func TestListTrash_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deletedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().ListTrash(gomock.Any(), domain.ListFilter{ServiceName: "Netflix"}, domain.PageRequest{}).
+		Return(domain.SubscriptionPage{Items: []domain.Subscription{{ID: "id-1", ServiceName: "Netflix", DeletedAt: &deletedAt}}}, nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/trash?service_name=Netflix", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.SubscriptionListResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Items, 1)
+	require.NotNil(t, resp.Items[0].DeletedAt)
+	require.True(t, deletedAt.Equal(*resp.Items[0].DeletedAt))
+}
+
+func TestRestoreSubscription_NotInTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Restore(gomock.Any(), id).Return(domain.ErrSubscriptionNotFound)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+id+"/restore", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPauseSubscription_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Pause(gomock.Any(), id, "06-2025").Return(nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+id+"/pause", bytes.NewReader([]byte(`{"from":"06-2025"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestResumeSubscription_NotPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Resume(gomock.Any(), gomock.Any(), "09-2025").Return(domain.ErrSubscriptionNotPaused)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+uuid.NewString()+"/resume", bytes.NewReader([]byte(`{"from":"09-2025"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetSubscription_Pauses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	resumed := "09-2025"
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{
+		ID:        id,
+		StartDate: "01-2025",
+		Pauses:    []domain.Pause{{PausedFrom: "06-2025", ResumedFrom: &resumed}, {PausedFrom: "12-2025"}},
+	}, nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id, nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.SubscriptionResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []httpapi.PauseResponse{
+		{PausedFrom: "06-2025", ResumedFrom: &resumed},
+		{PausedFrom: "12-2025"},
+	}, resp.Pauses)
+}
+
+func TestCancelSubscription_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Cancel(gomock.Any(), id, domain.Cancellation{EffectiveMonth: "09-2025", Reason: "moving abroad"}).Return(nil)
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"effective_month":"09-2025","reason":"moving abroad"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+id+"/cancel", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCancelSubscription_IllegalTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Cancel(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&domain.TransitionError{From: domain.StatusExpired, To: domain.StatusCancelled})
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"effective_month":"09-2025"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+uuid.NewString()+"/cancel", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestChangeSubscriptionPrice_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().ChangePrice(gomock.Any(), domain.PriceChange{SubscriptionID: id, Price: 600, EffectiveFrom: "09-2025"}).Return(nil)
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"price":600,"effective_from":"09-2025"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+id+"/price-changes", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestChangeSubscriptionPrice_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().ChangePrice(gomock.Any(), gomock.Any()).Return(domain.ErrSubscriptionNotFound)
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"price":600,"effective_from":"09-2025"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/"+uuid.NewString()+"/price-changes", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSubscriptionHistory_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().History(gomock.Any(), id).Return([]domain.SubscriptionEvent{
+		{ID: 1, SubscriptionID: id, Action: domain.EventCreate, After: json.RawMessage(`{"price":400}`), Actor: "alice"},
+		{ID: 2, SubscriptionID: id, Action: domain.EventPurge, Before: json.RawMessage(`{"price":400}`), Actor: domain.SystemActor},
+	}, nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/"+id+"/history", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]any
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	require.Equal(t, "create", resp[0]["action"])
+	require.Nil(t, resp[0]["before"])
+	require.Equal(t, map[string]any{"price": float64(400)}, resp[0]["after"])
+	require.Nil(t, resp[1]["after"])
+}
+
+func TestDeleteSubscription_AuditContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Delete(gomock.Any(), gomock.Any(), int64(0)).
+		DoAndReturn(func(ctx context.Context, _ string, _ int64) error {
+			require.Equal(t, testSubject, domain.ActorFromContext(ctx))
+			require.Equal(t, "req-42", domain.RequestIDFromContext(ctx))
+			return nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/subscriptions/"+uuid.NewString(), nil)
+	req.Header.Set("X-Request-Id", "req-42")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestTotalSubscriptions_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	svc := NewMocksubscriptionService(ctrl)
 	svc.EXPECT().Total(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(_ context.Context, filter domain.Subscription) (int64, error) {
-			require.Equal(t, "07-2025", filter.StartDate)
-			require.NotNil(t, filter.EndDate)
-			require.Equal(t, "08-2025", *filter.EndDate)
-			return 300, nil
+		DoAndReturn(func(_ context.Context, filter domain.TotalFilter) (int64, string, error) {
+			require.Equal(t, "07-2025", filter.From)
+			require.Equal(t, "08-2025", filter.To)
+			require.Equal(t, domain.ProrationDaily, filter.Proration)
+			require.Empty(t, filter.Currency)
+			return 300, "USD", nil
 		})
-	log := logger.NewNoop()
-	apiHandler := httpapi.NewSubscriptionHandler(log, svc)
-	h := httpapi.NewHandler(log, apiHandler)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/total?from=07-2025&to=08-2025&proration=daily", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.TotalResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotNil(t, resp.Total)
+	require.Equal(t, int64(300), *resp.Total)
+	require.Equal(t, "USD", resp.Currency)
+}
+
+func TestTotalSubscriptions_MissingRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Total(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter domain.TotalFilter) (int64, string, error) {
+			require.Equal(t, "EUR", filter.Currency)
+			return 0, "", fmt.Errorf("%w: USD to EUR", domain.ErrMissingExchangeRate)
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/total?from=07-2025&to=08-2025&currency=EUR", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestTotalSubscriptions_GroupBy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().TotalBreakdown(gomock.Any(), gomock.Any(), "service_name").
+		Return([]domain.TotalBucket{
+			{Key: "Netflix", Amount: 200, Count: 1},
+			{Key: "Spotify", Amount: 100, Count: 2},
+		}, domain.DefaultCurrency, nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/total?from=07-2025&to=08-2025&group_by=service_name", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.TotalResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotNil(t, resp.Total)
+	require.Equal(t, int64(300), *resp.Total)
+	require.Equal(t, domain.DefaultCurrency, resp.Currency)
+	require.Equal(t, "service_name", resp.GroupBy)
+	require.Len(t, resp.Buckets, 2)
+	require.Equal(t, "Spotify", resp.Buckets[1].Key)
+	require.Equal(t, int64(2), resp.Buckets[1].Count)
+}
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/total?from=07-2025&to=08-2025", nil)
+func TestTotalSubscriptions_GroupByCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().TotalBreakdown(gomock.Any(), gomock.Any(), "currency").
+		Return([]domain.TotalBucket{
+			{Key: "EUR", Amount: 20, Count: 1},
+			{Key: "RUB", Amount: 600, Count: 1},
+		}, "", nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/total?from=07-2025&to=08-2025&group_by=currency", nil)
 	w := httptest.NewRecorder()
 
 	h.ServeHTTP(w, req)
 
+	// Buckets in different currencies have no common total.
 	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"total"`)
+	require.NotContains(t, w.Body.String(), `"currency":`)
 	var resp httpapi.TotalResponse
 	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
-	require.Equal(t, int64(300), resp.Total)
+	require.Len(t, resp.Buckets, 2)
+	require.Equal(t, int64(20), resp.Buckets[0].Amount)
+}
+
+func TestTimeseriesSubscriptions_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Timeseries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error) {
+			require.Equal(t, "07-2025", filter.From)
+			require.Equal(t, "08-2025", filter.To)
+			return []domain.TimeseriesPoint{
+				{Month: "07-2025", Amount: 100, Active: 1, Started: 1},
+				{Month: "08-2025", Amount: 100, Active: 1, Ended: 1},
+			}, domain.DefaultCurrency, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/timeseries?from=07-2025&to=08-2025", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.TimeseriesResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Points, 2)
+	require.Equal(t, "07-2025", resp.Points[0].Month)
+	require.Equal(t, int64(1), resp.Points[0].Started)
+}
+
+func TestBatchSubscriptions_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Batch(gomock.Any(), domain.BatchBestEffort, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ domain.BatchMode, ops []domain.BatchOperation) ([]domain.BatchResult, error) {
+			require.Len(t, ops, 3)
+			require.Equal(t, domain.BatchCreate, ops[0].Action)
+			require.Equal(t, "Netflix", ops[0].Subscription.ServiceName)
+			require.Equal(t, domain.BatchUpdate, ops[1].Action)
+			require.Equal(t, id, ops[1].ID)
+			require.Equal(t, domain.BatchDelete, ops[2].Action)
+			require.Equal(t, int64(4), ops[2].Version)
+			return []domain.BatchResult{
+				{Action: domain.BatchCreate, ID: "id-1", Status: domain.BatchStatusOK},
+				{Action: domain.BatchUpdate, ID: id, Status: domain.BatchStatusFailed, Err: &domain.ValidationError{Err: domain.NewFieldError("price", domain.ErrInvalidPrice)}},
+				{Action: domain.BatchDelete, ID: id, Status: domain.BatchStatusFailed, Err: errors.New("connection refused")},
+			}, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	body := []byte(`{"mode":"best_effort","operations":[
+		{"op":"create","subscription":{"service_name":"Netflix","price":400,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}},
+		{"op":"update","id":"` + id + `","subscription":{"service_name":"Netflix","price":-1}},
+		{"op":"delete","id":"` + id + `","version":4}
+	]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.BatchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, 1, resp.Succeeded)
+	require.Equal(t, 2, resp.Failed)
+	require.Equal(t, httpapi.BatchResultResponse{Index: 0, Op: "create", ID: "id-1", Status: "ok", Code: http.StatusCreated}, resp.Results[0])
+	require.Equal(t, http.StatusBadRequest, resp.Results[1].Code)
+	require.Equal(t, "price: "+domain.ErrInvalidPrice.Error(), resp.Results[1].Error)
+	require.Equal(t, []httpapi.FieldErrorResponse{
+		{Field: "operations[1].subscription.price", Code: "invalid_price", Message: domain.ErrInvalidPrice.Error()},
+	}, resp.Results[1].Errors)
+	require.Equal(t, http.StatusInternalServerError, resp.Results[2].Code)
+	require.Equal(t, "internal server error", resp.Results[2].Error)
+}
+
+func TestBatchSubscriptions_InvalidBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Batch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, &domain.ValidationError{Err: domain.ErrInvalidBatchSize})
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/batch", bytes.NewReader([]byte(`{"operations":[]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func newImportRequest(query string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/import"+query, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	return req
+}
+
+func TestImportSubscriptions_ReadsRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.NewString()
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Import(gomock.Any(), gomock.Any(), true).
+		DoAndReturn(func(_ context.Context, rows iter.Seq2[domain.ImportRow, error], dryRun bool) (domain.ImportReport, error) {
+			var got []domain.ImportRow
+			for row, err := range rows {
+				require.NoError(t, err)
+				got = append(got, row)
+			}
+			require.Len(t, got, 3)
+
+			require.Equal(t, 2, got[0].Line)
+			require.NoError(t, got[0].Err)
+			require.Equal(t, "Netflix", got[0].Subscription.ServiceName)
+			require.Equal(t, 400, got[0].Subscription.Price)
+			require.Equal(t, userID, got[0].Subscription.UserID)
+			require.Equal(t, "2025-07-15", got[0].Subscription.StartDate)
+			require.Nil(t, got[0].Subscription.EndDate)
+			require.Equal(t, "09-2025", *got[1].Subscription.EndDate)
+			// The apostrophe an export puts before a formula is dropped again.
+			require.Equal(t, "=Yandex, Plus", got[1].Subscription.ServiceName)
+
+			// An unreadable price is reported with the row's other columns still read.
+			require.Equal(t, 5, got[2].Line)
+			require.NoError(t, got[2].Err)
+			require.ErrorIs(t, got[2].Invalid.Err(), domain.ErrInvalidPrice)
+			require.Equal(t, "Spotify", got[2].Subscription.ServiceName)
+
+			report := domain.ImportReport{DryRun: dryRun, Rows: 3}
+			report.AddError(got[2].Line, got[2].Invalid.Err())
+			return report, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	body := "\ufeffuser_id,service_name,price,start_date,end_date\n" +
+		userID + ",Netflix,400,2025-07-15,\n" +
+		userID + ",\"'=Yandex, Plus\",300,07-2025,09-2025\n\n" +
+		userID + ",Spotify,free,07-2025,\n"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newImportRequest("?dry_run=true", body))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp httpapi.ImportResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []httpapi.ImportErrorResponse{{
+		Line:  5,
+		Error: "price: " + domain.ErrInvalidPrice.Error(),
+		Errors: []httpapi.FieldErrorResponse{
+			{Field: "lines[5].price", Code: "invalid_price", Message: domain.ErrInvalidPrice.Error()},
+		},
+	}}, resp.Errors)
+}
+
+func TestImportSubscriptions_FailedImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Import(gomock.Any(), gomock.Any(), false).Return(domain.ImportReport{Rows: 1, Failed: 1}, nil)
+	h := newTestRouter(ctrl, svc)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newImportRequest("", "service_name,price,user_id,start_date\nNetflix,-1,x,07-2025\n"))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestImportSubscriptions_BadRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		body   string
+		status int
+	}{
+		{name: "empty", body: "", status: http.StatusBadRequest},
+		{name: "unknown column", body: "service_name,price,user_id,start_date,plan\n", status: http.StatusBadRequest},
+		{name: "missing column", body: "service_name,price,start_date\n", status: http.StatusBadRequest},
+		{name: "duplicate column", body: "service_name,price,user_id,start_date,price\n", status: http.StatusBadRequest},
+		{name: "dry run flag", query: "?dry_run=maybe", body: "service_name,price,user_id,start_date\n", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newImportRequest(tt.query, tt.body))
+
+			require.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	ctrl := gomock.NewController(t)
+	h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+	req := newImportRequest("", "service_name,price,user_id,start_date\n")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestImportSubscriptions_BodyTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Import(gomock.Any(), gomock.Any(), false).
+		DoAndReturn(func(_ context.Context, rows iter.Seq2[domain.ImportRow, error], _ bool) (domain.ImportReport, error) {
+			for _, err := range rows {
+				if err != nil {
+					return domain.ImportReport{}, err
+				}
+			}
+			return domain.ImportReport{}, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	row := "Netflix,400," + uuid.NewString() + ",07-2025\n"
+	body := "service_name,price,user_id,start_date\n" + strings.Repeat(row, domain.MaxImportBytes/len(row)+1)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newImportRequest("", body))
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestImportSubscriptions_MalformedRow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Import(gomock.Any(), gomock.Any(), false).
+		DoAndReturn(func(_ context.Context, rows iter.Seq2[domain.ImportRow, error], _ bool) (domain.ImportReport, error) {
+			var lines []int
+			for row, err := range rows {
+				if err != nil {
+					return domain.ImportReport{}, err
+				}
+				require.Error(t, row.Err)
+				lines = append(lines, row.Line)
+			}
+			return domain.ImportReport{}, nil
+		})
+	h := newTestRouter(ctrl, svc)
+
+	body := "service_name,price,user_id,start_date\nNetflix,400\n\"Netflix,400,x,07-2025\n"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newImportRequest("", body))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "invalid csv")
+}
+
+func exportSeq(subs ...domain.Subscription) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		for _, sub := range subs {
+			if !yield(sub, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestExportSubscriptions_Formats(t *testing.T) {
+	endDate := "09-2025"
+	subs := []domain.Subscription{
+		{ID: "id-1", ServiceName: "Netflix", Price: 400, Currency: "RUB", BillingPeriod: domain.BillingMonthly,
+			UserID: "user-1", StartDate: "07-2025", Status: domain.StatusActive},
+		{ID: "id-2", ServiceName: "Yandex, Plus", Price: 300, Currency: "RUB", BillingPeriod: domain.BillingMonthly,
+			UserID: "user-1", StartDate: "07-2025", EndDate: &endDate, Status: domain.StatusExpired},
+	}
+
+	tests := []struct {
+		format      string
+		contentType string
+		check       func(t *testing.T, body []byte)
+	}{
+		{
+			format:      "csv",
+			contentType: "text/csv; charset=utf-8",
+			check: func(t *testing.T, body []byte) {
+				require.Equal(t,
+					"id,service_name,price,currency,billing_period,user_id,start_date,end_date,trial_end,status,cancelled_at,cancel_reason\n"+
+						"id-1,Netflix,400,RUB,monthly,user-1,07-2025,,,active,,\n"+
+						"id-2,\"Yandex, Plus\",300,RUB,monthly,user-1,07-2025,09-2025,,expired,,\n",
+					string(body))
+			},
+		},
+		{
+			format:      "ndjson",
+			contentType: "application/x-ndjson",
+			check: func(t *testing.T, body []byte) {
+				dec := json.NewDecoder(bytes.NewReader(body))
+				for _, sub := range subs {
+					var resp httpapi.SubscriptionResponse
+					require.NoError(t, dec.Decode(&resp))
+					require.Equal(t, sub.ID, resp.ID)
+				}
+				require.False(t, dec.More())
+			},
+		},
+		{
+			format:      "xlsx",
+			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			check: func(t *testing.T, body []byte) {
+				zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				require.NoError(t, err)
+				sheet, err := zr.Open("xl/worksheets/sheet1.xml")
+				require.NoError(t, err)
+				content, err := io.ReadAll(sheet)
+				require.NoError(t, err)
+				require.Contains(t, string(content), "service_name")
+				require.Contains(t, string(content), "Yandex, Plus")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc := NewMocksubscriptionService(ctrl)
+			svc.EXPECT().
+				Export(gomock.Any(), domain.ListFilter{UserID: "user-1"}).
+				Return(exportSeq(subs...), nil)
+			h := newTestRouter(ctrl, svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/export?user_id=user-1&format="+tt.format, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			require.Regexp(t, `^attachment; filename=subscriptions-\d{4}-\d{2}-\d{2}\.`+tt.format+`$`,
+				w.Header().Get("Content-Disposition"))
+			tt.check(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestExportSubscriptions_NeutralisesFormulas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Export(gomock.Any(), domain.ListFilter{}).Return(exportSeq(domain.Subscription{
+		ID: "id-1", ServiceName: `=HYPERLINK("http://evil.example","Netflix")`, Price: 400, Currency: "RUB",
+		BillingPeriod: domain.BillingMonthly, UserID: "user-1", StartDate: "07-2025", Status: domain.StatusCancelled,
+		CancelReason: "@SUM(1+1)",
+	}), nil)
+	h := newTestRouter(ctrl, svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/export?format=csv", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, `'=HYPERLINK("http://evil.example","Netflix")`, records[1][1])
+	require.Equal(t, "'@SUM(1+1)", records[1][11])
+}
+
+func TestExportSubscriptions_DefaultsToCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Export(gomock.Any(), domain.ListFilter{}).Return(exportSeq(), nil)
+	h := newTestRouter(ctrl, svc)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/export", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Equal(t,
+		"id,service_name,price,currency,billing_period,user_id,start_date,end_date,trial_end,status,cancelled_at,cancel_reason\n",
+		w.Body.String())
+}
+
+func TestExportSubscriptions_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := newTestRouter(ctrl, NewMocksubscriptionService(ctrl))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/export?format=pdf", nil))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp httpapi.ProblemResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []httpapi.FieldErrorResponse{
+		{Field: "format", Code: "invalid_export_format", Message: domain.ErrInvalidExportFormat.Error()},
+	}, resp.Errors)
+}
+
+func TestExportSubscriptions_ErrorBeforeFirstRow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := NewMocksubscriptionService(ctrl)
+	svc.EXPECT().Export(gomock.Any(), gomock.Any()).Return(func(yield func(domain.Subscription, error) bool) {
+		yield(domain.Subscription{}, errors.New("connection reset"))
+	}, nil)
+	h := newTestRouter(ctrl, svc)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/export?format=ndjson", nil))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Empty(t, w.Header().Get("Content-Disposition"))
 }
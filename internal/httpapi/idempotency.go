@@ -0,0 +1,112 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"subscription_service/internal/domain"
+	"subscription_service/pkg/logger"
+)
+
+const (
+	// IdempotencyKeyHeader makes a POST safe to retry: a repeat with the same key and body gets the
+	// original response instead of being handled again.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed for a retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type IdempotencyMiddleware struct {
+	log     logger.Logger
+	service idempotencyService
+}
+
+func NewIdempotencyMiddleware(log logger.Logger, service idempotencyService) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{log: log, service: service}
+}
+
+// Handler stores the response of requests carrying an Idempotency-Key and replays it to retries.
+// Server errors are not stored, so such requests can be retried with the same key.
+func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			newErrorResponse(w, r, http.StatusBadRequest, ErrInvalidJSON)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the caller, so a key reused by another caller is a different key.
+		actor := domain.ActorFromContext(r.Context())
+		stored, err := m.service.Begin(r.Context(), actor, key, requestHash(r, body))
+		if err != nil {
+			handleError(m.log, w, r, err, "begin idempotent request")
+			return
+		}
+		if stored != nil {
+			replay(w, *stored)
+			return
+		}
+
+		// The outcome is recorded even if the client has gone away in the meantime.
+		ctx := context.WithoutCancel(r.Context())
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := m.service.Release(ctx, actor, key); err != nil {
+				m.log.Error("release idempotency key", "error", err)
+			}
+		}()
+
+		var captured bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&captured)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		resp := domain.StoredResponse{StatusCode: status, ContentType: ww.Header().Get("Content-Type"), Body: captured.Bytes()}
+		if err := m.service.Complete(ctx, actor, key, resp); err != nil {
+			m.log.Error("complete idempotency key", "error", err)
+			return
+		}
+		completed = true
+	})
+}
+
+// requestHash identifies a request by its method, path and body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, resp domain.StoredResponse) {
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
@@ -0,0 +1,153 @@
+package httpapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	"subscription_service/internal/httpapi"
+	"subscription_service/pkg/logger"
+)
+
+func newIdempotentRouter(ctrl *gomock.Controller, svc *MocksubscriptionService, idempotency *MockidempotencyService) http.Handler {
+	log := logger.NewNoop()
+	return authenticated(httpapi.NewHandler(
+		log,
+		httpapi.NewSubscriptionHandler(log, svc),
+		httpapi.NewExchangeRateHandler(log, NewMockexchangeRateService(ctrl)),
+		httpapi.NewAPIKeyHandler(log, NewMockapiKeyService(ctrl)),
+		httpapi.NewIdempotencyMiddleware(log, idempotency),
+		newTestAuth(),
+		httpapi.NewAPIKeyMiddleware(log, NewMockapiKeyAuthenticator(ctrl)),
+	))
+}
+
+func newCreateRequest(key string) *http.Request {
+	body := []byte(`{"service_name":"Netflix","price":400,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(httpapi.IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestCreateSubscription_IdempotencyKeyStoresResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewMocksubscriptionService(ctrl)
+	idempotency := NewMockidempotencyService(ctrl)
+
+	idempotency.EXPECT().Begin(gomock.Any(), testSubject, "key-1", gomock.Any()).Return(nil, nil)
+	svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("id-123", nil)
+	idempotency.EXPECT().Complete(gomock.Any(), testSubject, "key-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, resp domain.StoredResponse) error {
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+			require.Equal(t, "application/json; charset=utf-8", resp.ContentType)
+			require.JSONEq(t, `{"id":"id-123"}`, string(resp.Body))
+			return nil
+		})
+	h := newIdempotentRouter(ctrl, svc, idempotency)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newCreateRequest("key-1"))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Empty(t, w.Header().Get(httpapi.IdempotentReplayedHeader))
+}
+
+func TestCreateSubscription_IdempotencyKeyReplays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	idempotency := NewMockidempotencyService(ctrl)
+
+	idempotency.EXPECT().Begin(gomock.Any(), testSubject, "key-1", gomock.Any()).Return(&domain.StoredResponse{
+		StatusCode:  http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Body:        []byte(`{"id":"id-123"}`),
+	}, nil)
+	h := newIdempotentRouter(ctrl, NewMocksubscriptionService(ctrl), idempotency)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newCreateRequest("key-1"))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Equal(t, "true", w.Header().Get(httpapi.IdempotentReplayedHeader))
+	require.JSONEq(t, `{"id":"id-123"}`, w.Body.String())
+}
+
+func TestCreateSubscription_IdempotencyKeyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "different body", err: domain.ErrIdempotencyKeyReused, want: http.StatusUnprocessableEntity},
+		{name: "in progress", err: domain.ErrIdempotencyKeyInUse, want: http.StatusConflict},
+		{
+			name: "invalid key",
+			err:  &domain.ValidationError{Err: domain.NewFieldError("Idempotency-Key", domain.ErrInvalidIdempotencyKey)},
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			idempotency := NewMockidempotencyService(ctrl)
+			idempotency.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.err)
+			h := newIdempotentRouter(ctrl, NewMocksubscriptionService(ctrl), idempotency)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newCreateRequest("key-1"))
+
+			require.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusBadRequest {
+				require.Contains(t, w.Body.String(), `"field":"Idempotency-Key"`)
+			}
+		})
+	}
+}
+
+func TestCreateSubscription_IdempotencyKeyReleasedOnServerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewMocksubscriptionService(ctrl)
+	idempotency := NewMockidempotencyService(ctrl)
+
+	idempotency.EXPECT().Begin(gomock.Any(), testSubject, "key-1", gomock.Any()).Return(nil, nil)
+	svc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("", errors.New("connection refused"))
+	idempotency.EXPECT().Release(gomock.Any(), testSubject, "key-1").Return(nil)
+	h := newIdempotentRouter(ctrl, svc, idempotency)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newCreateRequest("key-1"))
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCreateSubscription_SameBodySameHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	idempotency := NewMockidempotencyService(ctrl)
+
+	var hashes []string
+	idempotency.EXPECT().Begin(gomock.Any(), testSubject, "key-1", gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, _ string, _ string, hash string) (*domain.StoredResponse, error) {
+			hashes = append(hashes, hash)
+			return nil, domain.ErrIdempotencyKeyInUse
+		})
+	h := newIdempotentRouter(ctrl, NewMocksubscriptionService(ctrl), idempotency)
+
+	body := `{"service_name":"Netflix","price":400,"user_id":"` + uuid.NewString() + `","start_date":"07-2025"}`
+	for _, b := range []string{body, body, body + " "} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/subscriptions/", bytes.NewReader([]byte(b)))
+		req.Header.Set(httpapi.IdempotencyKeyHeader, "key-1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Equal(t, hashes[0], hashes[1])
+	require.NotEqual(t, hashes[0], hashes[2])
+}
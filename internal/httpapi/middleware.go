@@ -0,0 +1,18 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"subscription_service/internal/domain"
+)
+
+// auditContext carries the request ID into the context, where the repository picks it up when it
+// records a change. It must run after middleware.RequestID. The actor is set by AuthMiddleware.
+func auditContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := domain.WithRequestID(r.Context(), middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -11,8 +11,10 @@ package httpapi_test
 
 import (
 	context "context"
+	iter "iter"
 	reflect "reflect"
 	domain "subscription_service/internal/domain"
+	jwt "subscription_service/pkg/jwt"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,49 @@ func (m *MocksubscriptionService) EXPECT() *MocksubscriptionServiceMockRecorder
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MocksubscriptionService) Batch(ctx context.Context, mode domain.BatchMode, ops []domain.BatchOperation) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, mode, ops)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MocksubscriptionServiceMockRecorder) Batch(ctx, mode, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MocksubscriptionService)(nil).Batch), ctx, mode, ops)
+}
+
+// Cancel mocks base method.
+func (m *MocksubscriptionService) Cancel(ctx context.Context, id string, cancellation domain.Cancellation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, cancellation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MocksubscriptionServiceMockRecorder) Cancel(ctx, id, cancellation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MocksubscriptionService)(nil).Cancel), ctx, id, cancellation)
+}
+
+// ChangePrice mocks base method.
+func (m *MocksubscriptionService) ChangePrice(ctx context.Context, change domain.PriceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePrice", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePrice indicates an expected call of ChangePrice.
+func (mr *MocksubscriptionServiceMockRecorder) ChangePrice(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePrice", reflect.TypeOf((*MocksubscriptionService)(nil).ChangePrice), ctx, change)
+}
+
 // Create mocks base method.
 func (m *MocksubscriptionService) Create(ctx context.Context, sub domain.Subscription) (string, error) {
 	m.ctrl.T.Helper()
@@ -57,17 +102,32 @@ func (mr *MocksubscriptionServiceMockRecorder) Create(ctx, sub any) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MocksubscriptionService) Delete(ctx context.Context, id string) error {
+func (m *MocksubscriptionService) Delete(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MocksubscriptionServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MocksubscriptionServiceMockRecorder) Delete(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MocksubscriptionService)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MocksubscriptionService)(nil).Delete), ctx, id, version)
+}
+
+// Export mocks base method.
+func (m *MocksubscriptionService) Export(ctx context.Context, filter domain.ListFilter) (iter.Seq2[domain.Subscription, error], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter)
+	ret0, _ := ret[0].(iter.Seq2[domain.Subscription, error])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MocksubscriptionServiceMockRecorder) Export(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MocksubscriptionService)(nil).Export), ctx, filter)
 }
 
 // GetByID mocks base method.
@@ -85,28 +145,161 @@ func (mr *MocksubscriptionServiceMockRecorder) GetByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MocksubscriptionService)(nil).GetByID), ctx, id)
 }
 
+// History mocks base method.
+func (m *MocksubscriptionService) History(ctx context.Context, id string) ([]domain.SubscriptionEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]domain.SubscriptionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MocksubscriptionServiceMockRecorder) History(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MocksubscriptionService)(nil).History), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MocksubscriptionService) Import(ctx context.Context, rows iter.Seq2[domain.ImportRow, error], dryRun bool) (domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, rows, dryRun)
+	ret0, _ := ret[0].(domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MocksubscriptionServiceMockRecorder) Import(ctx, rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MocksubscriptionService)(nil).Import), ctx, rows, dryRun)
+}
+
 // List mocks base method.
-func (m *MocksubscriptionService) List(ctx context.Context, userID, serviceName string) ([]domain.Subscription, error) {
+func (m *MocksubscriptionService) List(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, userID, serviceName)
-	ret0, _ := ret[0].([]domain.Subscription)
+	ret := m.ctrl.Call(m, "List", ctx, filter, page)
+	ret0, _ := ret[0].(domain.SubscriptionPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MocksubscriptionServiceMockRecorder) List(ctx, userID, serviceName any) *gomock.Call {
+func (mr *MocksubscriptionServiceMockRecorder) List(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MocksubscriptionService)(nil).List), ctx, filter, page)
+}
+
+// ListPriceChanges mocks base method.
+func (m *MocksubscriptionService) ListPriceChanges(ctx context.Context, id string) ([]domain.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceChanges", ctx, id)
+	ret0, _ := ret[0].([]domain.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriceChanges indicates an expected call of ListPriceChanges.
+func (mr *MocksubscriptionServiceMockRecorder) ListPriceChanges(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MocksubscriptionService)(nil).List), ctx, userID, serviceName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceChanges", reflect.TypeOf((*MocksubscriptionService)(nil).ListPriceChanges), ctx, id)
+}
+
+// ListTrash mocks base method.
+func (m *MocksubscriptionService) ListTrash(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, filter, page)
+	ret0, _ := ret[0].(domain.SubscriptionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MocksubscriptionServiceMockRecorder) ListTrash(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MocksubscriptionService)(nil).ListTrash), ctx, filter, page)
+}
+
+// Patch mocks base method.
+func (m *MocksubscriptionService) Patch(ctx context.Context, id string, patch domain.SubscriptionPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, patch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MocksubscriptionServiceMockRecorder) Patch(ctx, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MocksubscriptionService)(nil).Patch), ctx, id, patch)
+}
+
+// Pause mocks base method.
+func (m *MocksubscriptionService) Pause(ctx context.Context, id, from string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MocksubscriptionServiceMockRecorder) Pause(ctx, id, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MocksubscriptionService)(nil).Pause), ctx, id, from)
+}
+
+// Restore mocks base method.
+func (m *MocksubscriptionService) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MocksubscriptionServiceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MocksubscriptionService)(nil).Restore), ctx, id)
+}
+
+// Resume mocks base method.
+func (m *MocksubscriptionService) Resume(ctx context.Context, id, from string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MocksubscriptionServiceMockRecorder) Resume(ctx, id, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MocksubscriptionService)(nil).Resume), ctx, id, from)
+}
+
+// Timeseries mocks base method.
+func (m *MocksubscriptionService) Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeseries", ctx, filter)
+	ret0, _ := ret[0].([]domain.TimeseriesPoint)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Timeseries indicates an expected call of Timeseries.
+func (mr *MocksubscriptionServiceMockRecorder) Timeseries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeseries", reflect.TypeOf((*MocksubscriptionService)(nil).Timeseries), ctx, filter)
 }
 
 // Total mocks base method.
-func (m *MocksubscriptionService) Total(ctx context.Context, filter domain.Subscription) (int64, error) {
+func (m *MocksubscriptionService) Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Total", ctx, filter)
 	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Total indicates an expected call of Total.
@@ -115,6 +308,22 @@ func (mr *MocksubscriptionServiceMockRecorder) Total(ctx, filter any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Total", reflect.TypeOf((*MocksubscriptionService)(nil).Total), ctx, filter)
 }
 
+// TotalBreakdown mocks base method.
+func (m *MocksubscriptionService) TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy string) ([]domain.TotalBucket, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalBreakdown", ctx, filter, groupBy)
+	ret0, _ := ret[0].([]domain.TotalBucket)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TotalBreakdown indicates an expected call of TotalBreakdown.
+func (mr *MocksubscriptionServiceMockRecorder) TotalBreakdown(ctx, filter, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalBreakdown", reflect.TypeOf((*MocksubscriptionService)(nil).TotalBreakdown), ctx, filter, groupBy)
+}
+
 // Update mocks base method.
 func (m *MocksubscriptionService) Update(ctx context.Context, sub domain.Subscription) error {
 	m.ctrl.T.Helper()
@@ -128,3 +337,285 @@ func (mr *MocksubscriptionServiceMockRecorder) Update(ctx, sub any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MocksubscriptionService)(nil).Update), ctx, sub)
 }
+
+// MockexchangeRateService is a mock of exchangeRateService interface.
+type MockexchangeRateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockexchangeRateServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockexchangeRateServiceMockRecorder is the mock recorder for MockexchangeRateService.
+type MockexchangeRateServiceMockRecorder struct {
+	mock *MockexchangeRateService
+}
+
+// NewMockexchangeRateService creates a new mock instance.
+func NewMockexchangeRateService(ctrl *gomock.Controller) *MockexchangeRateService {
+	mock := &MockexchangeRateService{ctrl: ctrl}
+	mock.recorder = &MockexchangeRateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockexchangeRateService) EXPECT() *MockexchangeRateServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockexchangeRateService) Delete(ctx context.Context, base, quote string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, base, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockexchangeRateServiceMockRecorder) Delete(ctx, base, quote any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockexchangeRateService)(nil).Delete), ctx, base, quote)
+}
+
+// List mocks base method.
+func (m *MockexchangeRateService) List(ctx context.Context) ([]domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockexchangeRateServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockexchangeRateService)(nil).List), ctx)
+}
+
+// Set mocks base method.
+func (m *MockexchangeRateService) Set(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, rate)
+	ret0, _ := ret[0].(domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockexchangeRateServiceMockRecorder) Set(ctx, rate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockexchangeRateService)(nil).Set), ctx, rate)
+}
+
+// MockapiKeyService is a mock of apiKeyService interface.
+type MockapiKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockapiKeyServiceMockRecorder is the mock recorder for MockapiKeyService.
+type MockapiKeyServiceMockRecorder struct {
+	mock *MockapiKeyService
+}
+
+// NewMockapiKeyService creates a new mock instance.
+func NewMockapiKeyService(ctrl *gomock.Controller) *MockapiKeyService {
+	mock := &MockapiKeyService{ctrl: ctrl}
+	mock.recorder = &MockapiKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeyService) EXPECT() *MockapiKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockapiKeyService) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(domain.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockapiKeyServiceMockRecorder) Create(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockapiKeyService)(nil).Create), ctx, key)
+}
+
+// List mocks base method.
+func (m *MockapiKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockapiKeyServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockapiKeyService)(nil).List), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockapiKeyService) Revoke(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockapiKeyServiceMockRecorder) Revoke(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockapiKeyService)(nil).Revoke), ctx, id)
+}
+
+// MockapiKeyAuthenticator is a mock of apiKeyAuthenticator interface.
+type MockapiKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeyAuthenticatorMockRecorder
+	isgomock struct{}
+}
+
+// MockapiKeyAuthenticatorMockRecorder is the mock recorder for MockapiKeyAuthenticator.
+type MockapiKeyAuthenticatorMockRecorder struct {
+	mock *MockapiKeyAuthenticator
+}
+
+// NewMockapiKeyAuthenticator creates a new mock instance.
+func NewMockapiKeyAuthenticator(ctrl *gomock.Controller) *MockapiKeyAuthenticator {
+	mock := &MockapiKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockapiKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeyAuthenticator) EXPECT() *MockapiKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockapiKeyAuthenticator) Authenticate(ctx context.Context, secret string) (domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, secret)
+	ret0, _ := ret[0].(domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockapiKeyAuthenticatorMockRecorder) Authenticate(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockapiKeyAuthenticator)(nil).Authenticate), ctx, secret)
+}
+
+// MocktokenVerifier is a mock of tokenVerifier interface.
+type MocktokenVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MocktokenVerifierMockRecorder is the mock recorder for MocktokenVerifier.
+type MocktokenVerifierMockRecorder struct {
+	mock *MocktokenVerifier
+}
+
+// NewMocktokenVerifier creates a new mock instance.
+func NewMocktokenVerifier(ctrl *gomock.Controller) *MocktokenVerifier {
+	mock := &MocktokenVerifier{ctrl: ctrl}
+	mock.recorder = &MocktokenVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenVerifier) EXPECT() *MocktokenVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MocktokenVerifier) Verify(token string) (jwt.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", token)
+	ret0, _ := ret[0].(jwt.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MocktokenVerifierMockRecorder) Verify(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MocktokenVerifier)(nil).Verify), token)
+}
+
+// MockidempotencyService is a mock of idempotencyService interface.
+type MockidempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyServiceMockRecorder is the mock recorder for MockidempotencyService.
+type MockidempotencyServiceMockRecorder struct {
+	mock *MockidempotencyService
+}
+
+// NewMockidempotencyService creates a new mock instance.
+func NewMockidempotencyService(ctrl *gomock.Controller) *MockidempotencyService {
+	mock := &MockidempotencyService{ctrl: ctrl}
+	mock.recorder = &MockidempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyService) EXPECT() *MockidempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockidempotencyService) Begin(ctx context.Context, actor, key, requestHash string) (*domain.StoredResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, actor, key, requestHash)
+	ret0, _ := ret[0].(*domain.StoredResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockidempotencyServiceMockRecorder) Begin(ctx, actor, key, requestHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockidempotencyService)(nil).Begin), ctx, actor, key, requestHash)
+}
+
+// Complete mocks base method.
+func (m *MockidempotencyService) Complete(ctx context.Context, actor, key string, resp domain.StoredResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, actor, key, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockidempotencyServiceMockRecorder) Complete(ctx, actor, key, resp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockidempotencyService)(nil).Complete), ctx, actor, key, resp)
+}
+
+// Release mocks base method.
+func (m *MockidempotencyService) Release(ctx context.Context, actor, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, actor, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockidempotencyServiceMockRecorder) Release(ctx, actor, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockidempotencyService)(nil).Release), ctx, actor, key)
+}
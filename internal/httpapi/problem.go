@@ -0,0 +1,93 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"subscription_service/internal/domain"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem types are relative URIs. A validation problem lists the invalid fields in errors; the
+// other types only restate the status code.
+const (
+	problemTypePrefix     = "/problems/"
+	validationProblemType = problemTypePrefix + "validation-error"
+)
+
+// ProblemResponse is an RFC 7807 problem detail.
+type ProblemResponse struct {
+	Type      string               `json:"type" example:"/problems/validation-error"`
+	Title     string               `json:"title" example:"Validation failed"`
+	Status    int                  `json:"status" example:"400"`
+	Detail    string               `json:"detail,omitempty" example:"price: invalid price"`
+	Instance  string               `json:"instance,omitempty" example:"/api/v1/subscriptions"`
+	RequestID string               `json:"request_id,omitempty"`
+	Errors    []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+// FieldErrorResponse is one invalid field of a validation problem. Field is empty when the error
+// is not about a single field.
+type FieldErrorResponse struct {
+	Field   string `json:"field,omitempty" example:"price"`
+	Code    string `json:"code" example:"invalid_price"`
+	Message string `json:"message" example:"invalid price"`
+}
+
+// newProblem describes err, which is shown to the client, as the problem of request r.
+func newProblem(r *http.Request, status int, err error) ProblemResponse {
+	problem := ProblemResponse{
+		Type:      problemTypePrefix + problemSlug(http.StatusText(status)),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    err.Error(),
+		Instance:  r.URL.Path,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+
+	var vErr *domain.ValidationError
+	if errors.As(err, &vErr) {
+		problem.Type = validationProblemType
+		problem.Title = "Validation failed"
+		problem.Errors = fieldErrors(vErr, "", nil)
+	}
+
+	return problem
+}
+
+// fieldErrors lists the invalid fields of a validation error with prefix prepended to their paths.
+// Fields named in nested are further prefixed with the nested object, as subscription fields are
+// within a batch operation. Other errors have no fields.
+func fieldErrors(err error, prefix string, nested func(field string) string) []FieldErrorResponse {
+	var vErr *domain.ValidationError
+	if !errors.As(err, &vErr) {
+		return nil
+	}
+
+	fields := vErr.Fields()
+	result := make([]FieldErrorResponse, len(fields))
+	for i, field := range fields {
+		path := field.Field
+		if nested != nil && path != "" {
+			path = nested(path)
+		}
+		switch {
+		case prefix == "":
+		case path == "":
+			path = prefix
+		default:
+			path = prefix + "." + path
+		}
+		result[i] = FieldErrorResponse{Field: path, Code: field.Code, Message: field.Err.Error()}
+	}
+	return result
+}
+
+// problemSlug turns a status text such as "Not Found" into "not-found".
+func problemSlug(title string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(title, "-", " ")), " ", "-")
+}
@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"net/url"
+	"strconv"
+
+	"subscription_service/internal/domain"
+)
+
+func parseListFilter(query url.Values) (domain.ListFilter, error) {
+	filter := domain.ListFilter{
+		UserID:      query.Get("user_id"),
+		ServiceName: query.Get("service_name"),
+		Search:      query.Get("search"),
+		ActiveIn:    query.Get("active_in"),
+		StartFrom:   query.Get("start_from"),
+		StartTo:     query.Get("start_to"),
+		EndFrom:     query.Get("end_from"),
+		EndTo:       query.Get("end_to"),
+		Status:      domain.SubscriptionStatus(query.Get("status")),
+	}
+
+	var (
+		errs domain.FieldErrors
+		err  error
+	)
+	if filter.MinPrice, err = parseOptionalInt(query.Get("min_price")); err != nil {
+		errs.Add("min_price", domain.ErrInvalidMinPrice)
+	}
+
+	if filter.MaxPrice, err = parseOptionalInt(query.Get("max_price")); err != nil {
+		errs.Add("max_price", domain.ErrInvalidMaxPrice)
+	}
+
+	if raw := query.Get("open_ended"); raw != "" {
+		if filter.OpenEnded, err = strconv.ParseBool(raw); err != nil {
+			errs.Add("open_ended", domain.ErrInvalidOpenEnded)
+		}
+	}
+
+	if filter.TrialEndsWithin, err = parseOptionalInt(query.Get("trial_ends_within")); err != nil {
+		errs.Add("trial_ends_within", domain.ErrInvalidTrialWindow)
+	}
+
+	if err := errs.Err(); err != nil {
+		return domain.ListFilter{}, err
+	}
+	return filter, nil
+}
+
+// parsePeriodFilter reads the period and filters shared by the total and timeseries endpoints.
+func parsePeriodFilter(query url.Values) domain.TotalFilter {
+	return domain.TotalFilter{
+		UserID:      query.Get("user_id"),
+		ServiceName: query.Get("service_name"),
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+		Currency:    query.Get("currency"),
+		Proration:   domain.Proration(query.Get("proration")),
+	}
+}
+
+func parsePageRequest(query url.Values) (domain.PageRequest, error) {
+	page := domain.PageRequest{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+
+	limit, err := parseOptionalInt(query.Get("limit"))
+	if err != nil {
+		return domain.PageRequest{}, &domain.ValidationError{Err: domain.NewFieldError("limit", domain.ErrInvalidLimit)}
+	}
+	if limit != nil {
+		page.Limit = *limit
+	}
+
+	return page, nil
+}
+
+func parseOptionalInt(raw string) (*int, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// parseDryRun reads the optional dry_run flag.
+func parseDryRun(query url.Values) (bool, error) {
+	raw := query.Get("dry_run")
+	if raw == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, &domain.ValidationError{Err: domain.NewFieldError("dry_run", domain.ErrInvalidDryRun)}
+	}
+	return dryRun, nil
+}
+
+// parseExportFormat reads the optional export format, csv by default.
+func parseExportFormat(query url.Values) (exportFormat, error) {
+	raw := query.Get("format")
+	if raw == "" {
+		raw = "csv"
+	}
+
+	format, ok := exportFormats[raw]
+	if !ok {
+		return exportFormat{}, &domain.ValidationError{Err: domain.NewFieldError("format", domain.ErrInvalidExportFormat)}
+	}
+	return format, nil
+}
@@ -7,26 +7,72 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"subscription_service/internal/domain"
 	"subscription_service/pkg/logger"
 )
 
-func NewHandler(log logger.Logger, h *SubscriptionHandler) http.Handler {
+func NewHandler(
+	log logger.Logger, h *SubscriptionHandler, rates *ExchangeRateHandler, keys *APIKeyHandler,
+	idempotency *IdempotencyMiddleware, auth *AuthMiddleware, apiKeys *APIKeyMiddleware,
+) http.Handler {
 	r := chi.NewRouter()
-	r.Use(middleware.Recoverer, logger.GetLogMiddleware(log))
+	r.Use(middleware.RequestID, middleware.Recoverer, logger.GetLogMiddleware(log), auditContext)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		newErrorResponse(w, r, http.StatusNotFound, ErrRouteNotFound)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		newErrorResponse(w, r, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+	})
 
 	r.Head("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	r.Route("/api/v1/subscriptions", func(r chi.Router) {
-		r.Post("/", h.CreateSubscription)
-		r.Get("/", h.ListSubscriptions)
-		r.Get("/total", h.TotalSubscriptions)
+	// Every route names the permission it needs; see domain.Roles for what each role is granted.
+	read := auth.Require(domain.PermSubscriptionsRead)
+	write := auth.Require(domain.PermSubscriptionsWrite)
+	reports := auth.Require(domain.PermReportsRead)
+	admin := auth.Require(domain.PermAdmin)
+
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(apiKeys.Handler, auth.Handler)
+		r.Route("/subscriptions", func(r chi.Router) {
+			r.With(write, idempotency.Handler).Post("/", h.CreateSubscription)
+			r.With(read).Get("/", h.ListSubscriptions)
+			r.With(reports).Get("/export", h.ExportSubscriptions)
+			r.With(reports).Get("/total", h.TotalSubscriptions)
+			r.With(reports).Get("/timeseries", h.TimeseriesSubscriptions)
+			r.With(read).Get("/trash", h.ListTrash)
+			r.With(write, idempotency.Handler).Post("/batch", h.BatchSubscriptions)
+			r.With(write).Post("/import", h.ImportSubscriptions)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(read).Get("/", h.GetSubscription)
+				r.With(write).Put("/", h.UpdateSubscription)
+				r.With(write).Patch("/", h.PatchSubscription)
+				r.With(write).Delete("/", h.DeleteSubscription)
+				r.With(write).Post("/restore", h.RestoreSubscription)
+				r.With(write).Post("/pause", h.PauseSubscription)
+				r.With(write).Post("/resume", h.ResumeSubscription)
+				r.With(write).Post("/cancel", h.CancelSubscription)
+				r.With(write).Post("/price-changes", h.ChangeSubscriptionPrice)
+				r.With(read).Get("/price-changes", h.ListSubscriptionPriceChanges)
+				r.With(read).Get("/history", h.SubscriptionHistory)
+			})
+		})
+
+		r.Route("/admin/exchange-rates", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/", rates.ListExchangeRates)
+			r.Put("/", rates.SetExchangeRate)
+			r.Delete("/{base}/{quote}", rates.DeleteExchangeRate)
+		})
 
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", h.GetSubscription)
-			r.Put("/", h.UpdateSubscription)
-			r.Delete("/", h.DeleteSubscription)
+		r.Route("/admin/api-keys", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/", keys.ListAPIKeys)
+			r.Post("/", keys.CreateAPIKey)
+			r.Delete("/{id}", keys.RevokeAPIKey)
 		})
 	})
 
@@ -3,47 +3,117 @@ package httpapi
 import (
 	"encoding/json"
 	"errors"
+	"mime"
 	"net/http"
+
 	"subscription_service/internal/domain"
+	"subscription_service/pkg/logger"
 )
 
 func writeJSON(w http.ResponseWriter, status int, v any) error {
+	return writeBody(w, status, "application/json; charset=utf-8", v)
+}
+
+func writeBody(w http.ResponseWriter, status int, contentType string, v any) error {
 	data, err := json.Marshal(v)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Type", problemContentType)
 		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+		_ = json.NewEncoder(w).Encode(ProblemResponse{
+			Type:   problemTypePrefix + problemSlug(http.StatusText(http.StatusInternalServerError)),
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+			Detail: ErrStatusInternalServerError.Error(),
+		})
 		return err
 	}
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 	_, err = w.Write(data)
 	return err
 }
 
-func newErrorResponse(w http.ResponseWriter, status int, msg error) {
-	resp := map[string]string{"error": msg.Error()}
-	_ = writeJSON(w, status, resp)
+// newErrorResponse writes msg, which is shown to the client, as a problem+json response to r.
+func newErrorResponse(w http.ResponseWriter, r *http.Request, status int, msg error) {
+	_ = writeBody(w, status, problemContentType, newProblem(r, status, msg))
+}
+
+// isMergePatch reports whether a request body is a JSON merge patch. Plain JSON is accepted as well.
+func isMergePatch(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/merge-patch+json" || mediaType == "application/json"
 }
 
-func (h *SubscriptionHandler) handleError(w http.ResponseWriter, err error, operation string) {
+// isCSV reports whether a request body is CSV.
+func isCSV(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/csv"
+}
+
+func handleError(log logger.Logger, w http.ResponseWriter, r *http.Request, err error, operation string) {
+	status, public := classifyError(err)
+	if status == http.StatusInternalServerError {
+		log.Error("operation failed", "operation", operation, "error", err)
+	}
+	newErrorResponse(w, r, status, public)
+}
+
+// classifyError returns the HTTP status for err and the error to show to the client.
+// Unknown errors are internal and are not shown.
+func classifyError(err error) (int, error) {
 	var vErr *domain.ValidationError
 	if errors.As(err, &vErr) {
-		newErrorResponse(w, http.StatusBadRequest, vErr)
-		return
+		return http.StatusBadRequest, vErr
 	}
 
 	if errors.Is(err, domain.ErrSubscriptionNotFound) {
-		newErrorResponse(w, http.StatusNotFound, err)
-		return
+		return http.StatusNotFound, err
+	}
+
+	if errors.Is(err, domain.ErrSubscriptionPaused) || errors.Is(err, domain.ErrSubscriptionNotPaused) ||
+		errors.Is(err, domain.ErrInvalidTransition) {
+		return http.StatusConflict, err
+	}
+
+	if errors.Is(err, domain.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed, err
+	}
+
+	if errors.Is(err, domain.ErrIdempotencyKeyInUse) {
+		return http.StatusConflict, err
+	}
+
+	if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		return http.StatusUnprocessableEntity, err
+	}
+
+	if errors.Is(err, domain.ErrExchangeRateNotFound) || errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return http.StatusNotFound, err
+	}
+
+	if errors.Is(err, domain.ErrInvalidAPIKey) {
+		return http.StatusUnauthorized, err
+	}
+
+	if errors.Is(err, domain.ErrForbidden) {
+		return http.StatusForbidden, err
+	}
+
+	if errors.Is(err, domain.ErrImportTooLarge) {
+		return http.StatusRequestEntityTooLarge, err
+	}
+
+	if errors.Is(err, domain.ErrMissingExchangeRate) {
+		return http.StatusUnprocessableEntity, err
 	}
 
 	if errors.Is(err, domain.ErrNotImplemented) {
-		newErrorResponse(w, http.StatusNotImplemented, domain.ErrNotImplemented)
-		return
+		return http.StatusNotImplemented, domain.ErrNotImplemented
 	}
 
-	h.log.Error("operation failed", "operation", operation, "error", err)
-	newErrorResponse(w, http.StatusInternalServerError, ErrStatusInternalServerError)
+	return http.StatusInternalServerError, ErrStatusInternalServerError
 }
@@ -0,0 +1,100 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+const columns = `id, name, COALESCE(user_id::text, ''), scopes, expires_at, last_used_at, created_at, revoked_at`
+
+type Repository struct {
+	db dbExecutor
+}
+
+func New(db dbExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Create stores key under the hash of its secret and returns it with its ID and creation time.
+func (r *Repository) Create(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, key_hash, user_id, scopes, expires_at)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)
+		RETURNING ` + columns
+
+	created, err := scanAPIKey(r.db.QueryRow(ctx, query, key.Name, keyHash, key.UserID, key.Scopes, key.ExpiresAt))
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("create api key: %w", err)
+	}
+
+	return created, nil
+}
+
+// List returns every key, revoked and expired ones included, oldest first.
+func (r *Repository) List(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+columns+` FROM api_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		result = append(result, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api keys: %w", err)
+	}
+
+	return result, nil
+}
+
+// Revoke disables a key for good. A key that is unknown or already revoked is not found.
+func (r *Repository) Revoke(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate returns the key with the given secret hash and records that it was used. Revoked
+// and expired keys are rejected with ErrInvalidAPIKey, like unknown ones.
+func (r *Repository) Authenticate(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING ` + columns
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, keyHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.APIKey{}, fmt.Errorf("authenticate api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func scanAPIKey(row pgx.Row) (domain.APIKey, error) {
+	var key domain.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.UserID, &key.Scopes, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt, &key.RevokedAt)
+	return key, err
+}
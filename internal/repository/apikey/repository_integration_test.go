@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package apikey_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"subscription_service/internal/domain"
+	repository "subscription_service/internal/repository/apikey"
+	"subscription_service/pkg/testdb"
+)
+
+var testPool *pgxpool.Pool
+var teardown func()
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	dsn, cleanup, err := testdb.SetupTestDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to setup test db: %v\n", err)
+		os.Exit(1)
+	}
+	teardown = cleanup
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create pgx pool: %v\n", err)
+		teardown()
+		os.Exit(1)
+	}
+	testPool = pool
+
+	code := m.Run()
+
+	pool.Close()
+	teardown()
+	os.Exit(code)
+}
+
+func TestRepositoryCreateAuthenticate(t *testing.T) {
+	repo := repository.New(testPool)
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	created, err := repo.Create(ctx, domain.APIKey{Name: "billing", UserID: userID, Scopes: []string{"admin"}}, "hash-1")
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, userID, created.UserID)
+	require.Equal(t, []string{"admin"}, created.Scopes)
+	require.Nil(t, created.LastUsedAt)
+
+	key, err := repo.Authenticate(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, key.ID)
+	require.NotNil(t, key.LastUsedAt)
+
+	_, err = repo.Authenticate(ctx, "unknown")
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, keys)
+
+	require.NoError(t, repo.Revoke(ctx, created.ID))
+	require.ErrorIs(t, repo.Revoke(ctx, created.ID), domain.ErrAPIKeyNotFound)
+	_, err = repo.Authenticate(ctx, "hash-1")
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestRepositoryAuthenticateExpired(t *testing.T) {
+	repo := repository.New(testPool)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(-time.Minute)
+	_, err := repo.Create(ctx, domain.APIKey{Name: "expired", ExpiresAt: &expiresAt}, "hash-2")
+	require.NoError(t, err)
+
+	_, err = repo.Authenticate(ctx, "hash-2")
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
@@ -0,0 +1,74 @@
+package exchangerate
+
+import (
+	"context"
+	"fmt"
+
+	"subscription_service/internal/domain"
+)
+
+type Repository struct {
+	db dbExecutor
+}
+
+func New(db dbExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Upsert(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	query := `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate)
+		VALUES ($1, $2, $3::text::numeric)
+		ON CONFLICT (base_currency, quote_currency)
+		DO UPDATE SET rate = EXCLUDED.rate, updated_at = NOW()
+		RETURNING rate::text, updated_at
+	`
+
+	if err := r.db.QueryRow(ctx, query, rate.Base, rate.Quote, rate.Rate).Scan(&rate.Rate, &rate.UpdatedAt); err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("upsert exchange rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+func (r *Repository) List(ctx context.Context) ([]domain.ExchangeRate, error) {
+	query := `
+		SELECT base_currency, quote_currency, rate::text, updated_at
+		FROM exchange_rates
+		ORDER BY base_currency, quote_currency
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.ExchangeRate, 0)
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.Base, &rate.Quote, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan exchange rate: %w", err)
+		}
+		result = append(result, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate exchange rates: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, base string, quote string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM exchange_rates WHERE base_currency = $1 AND quote_currency = $2`, base, quote)
+	if err != nil {
+		return fmt.Errorf("delete exchange rate: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrExchangeRateNotFound
+	}
+
+	return nil
+}
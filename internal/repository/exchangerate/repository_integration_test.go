@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package exchangerate_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"subscription_service/internal/domain"
+	repository "subscription_service/internal/repository/exchangerate"
+	"subscription_service/pkg/testdb"
+)
+
+var testPool *pgxpool.Pool
+var teardown func()
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	dsn, cleanup, err := testdb.SetupTestDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to setup test db: %v\n", err)
+		os.Exit(1)
+	}
+	teardown = cleanup
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create pgx pool: %v\n", err)
+		teardown()
+		os.Exit(1)
+	}
+	testPool = pool
+
+	code := m.Run()
+
+	pool.Close()
+	teardown()
+	os.Exit(code)
+}
+
+func TestRepositoryUpsertListDelete(t *testing.T) {
+	repo := repository.New(testPool)
+
+	_, err := repo.Upsert(context.Background(), domain.ExchangeRate{Base: "EUR", Quote: "RUB", Rate: "98.5"})
+	require.NoError(t, err)
+
+	updated, err := repo.Upsert(context.Background(), domain.ExchangeRate{Base: "EUR", Quote: "RUB", Rate: "99.25"})
+	require.NoError(t, err)
+	require.Equal(t, "99.2500000000", updated.Rate)
+
+	rates, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	require.Equal(t, "EUR", rates[0].Base)
+
+	require.NoError(t, repo.Delete(context.Background(), "EUR", "RUB"))
+	require.ErrorIs(t, repo.Delete(context.Background(), "EUR", "RUB"), domain.ErrExchangeRateNotFound)
+}
@@ -0,0 +1,112 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+type Repository struct {
+	db dbExecutor
+}
+
+func New(db dbExecutor) *Repository {
+	return &Repository{db: db}
+}
+
+// Reserve claims key for a request of actor. Each actor has keys of its own. It reports true when
+// the key was free (or had expired); otherwise it returns the request that holds the key.
+func (r *Repository) Reserve(ctx context.Context, actor string, key string, requestHash string, expiresAt time.Time) (domain.IdempotencyKey, bool, error) {
+	if _, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE actor = $1 AND key = $2 AND expires_at <= NOW()`, actor, key); err != nil {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("delete expired idempotency key: %w", err)
+	}
+
+	insert := `
+		INSERT INTO idempotency_keys (actor, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (actor, key) DO NOTHING
+		RETURNING key
+	`
+
+	err := r.db.QueryRow(ctx, insert, actor, key, requestHash, expiresAt).Scan(&key)
+	if err == nil {
+		return domain.IdempotencyKey{Actor: actor, Key: key, RequestHash: requestHash, ExpiresAt: expiresAt}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	existing, err := r.get(ctx, actor, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The holder gave the key up between the two statements.
+		return domain.IdempotencyKey{}, false, domain.ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		return domain.IdempotencyKey{}, false, fmt.Errorf("get idempotency key: %w", err)
+	}
+
+	return existing, false, nil
+}
+
+func (r *Repository) get(ctx context.Context, actor string, key string) (domain.IdempotencyKey, error) {
+	query := `
+		SELECT actor, key, request_hash, status_code, COALESCE(content_type, ''), body, expires_at
+		FROM idempotency_keys
+		WHERE actor = $1 AND key = $2
+	`
+
+	var record domain.IdempotencyKey
+	var statusCode sql.NullInt32
+	var contentType string
+	var body []byte
+	err := r.db.QueryRow(ctx, query, actor, key).Scan(
+		&record.Actor, &record.Key, &record.RequestHash, &statusCode, &contentType, &body, &record.ExpiresAt,
+	)
+	if err != nil {
+		return domain.IdempotencyKey{}, err
+	}
+
+	if statusCode.Valid {
+		record.Response = &domain.StoredResponse{StatusCode: int(statusCode.Int32), ContentType: contentType, Body: body}
+	}
+
+	return record, nil
+}
+
+// Complete stores the response of the request holding key of actor.
+func (r *Repository) Complete(ctx context.Context, actor string, key string, resp domain.StoredResponse) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = NULLIF($4, ''), body = $5
+		WHERE actor = $1 AND key = $2
+	`
+
+	if _, err := r.db.Exec(ctx, query, actor, key, resp.StatusCode, resp.ContentType, resp.Body); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees a key whose request did not complete, so that it can be retried.
+func (r *Repository) Release(ctx context.Context, actor string, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE actor = $1 AND key = $2 AND status_code IS NULL`
+	if _, err := r.db.Exec(ctx, query, actor, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired removes expired keys and returns how many were removed.
+func (r *Repository) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
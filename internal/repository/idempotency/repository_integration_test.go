@@ -0,0 +1,124 @@
+//go:build integration
+// +build integration
+
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"subscription_service/internal/domain"
+	repository "subscription_service/internal/repository/idempotency"
+	"subscription_service/pkg/testdb"
+)
+
+var testPool *pgxpool.Pool
+var teardown func()
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	dsn, cleanup, err := testdb.SetupTestDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to setup test db: %v\n", err)
+		os.Exit(1)
+	}
+	teardown = cleanup
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create pgx pool: %v\n", err)
+		teardown()
+		os.Exit(1)
+	}
+	testPool = pool
+
+	code := m.Run()
+
+	pool.Close()
+	teardown()
+	os.Exit(code)
+}
+
+func TestRepositoryReserveComplete(t *testing.T) {
+	repo := repository.New(testPool)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, reserved, err := repo.Reserve(ctx, "alice", "key-1", "hash", expiresAt)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	existing, reserved, err := repo.Reserve(ctx, "alice", "key-1", "hash", expiresAt)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "hash", existing.RequestHash)
+	require.Nil(t, existing.Response)
+
+	resp := domain.StoredResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":"1"}`)}
+	require.NoError(t, repo.Complete(ctx, "alice", "key-1", resp))
+
+	existing, reserved, err = repo.Reserve(ctx, "alice", "key-1", "other", expiresAt)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "hash", existing.RequestHash)
+	require.Equal(t, &resp, existing.Response)
+
+	// A completed key is not released.
+	require.NoError(t, repo.Release(ctx, "alice", "key-1"))
+	_, reserved, err = repo.Reserve(ctx, "alice", "key-1", "hash", expiresAt)
+	require.NoError(t, err)
+	require.False(t, reserved)
+}
+
+func TestRepositoryReleaseAndExpiry(t *testing.T) {
+	repo := repository.New(testPool)
+	ctx := context.Background()
+
+	_, reserved, err := repo.Reserve(ctx, "alice", "key-2", "hash", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	require.NoError(t, repo.Release(ctx, "alice", "key-2"))
+	_, reserved, err = repo.Reserve(ctx, "alice", "key-2", "hash", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// The key above has already expired, so it is free again.
+	_, reserved, err = repo.Reserve(ctx, "alice", "key-2", "other", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	purged, err := repo.PurgeExpired(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, purged, int64(1))
+}
+
+func TestRepositoryKeysPerActor(t *testing.T) {
+	repo := repository.New(testPool)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, reserved, err := repo.Reserve(ctx, "alice", "key-3", "hash", expiresAt)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// Another actor reusing the key gets a key of its own.
+	_, reserved, err = repo.Reserve(ctx, "bob", "key-3", "other", expiresAt)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	resp := domain.StoredResponse{StatusCode: 201, Body: []byte(`{"id":"1"}`)}
+	require.NoError(t, repo.Complete(ctx, "bob", "key-3", resp))
+
+	existing, reserved, err := repo.Reserve(ctx, "alice", "key-3", "hash", expiresAt)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "alice", existing.Actor)
+	require.Nil(t, existing.Response)
+}
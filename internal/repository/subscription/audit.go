@@ -0,0 +1,149 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+// subscriptionSnapshot renders a subscription row aliased s, with its pauses and price changes, as JSON.
+const subscriptionSnapshot = `to_jsonb(s) || jsonb_build_object(
+			'pauses', (
+				SELECT COALESCE(jsonb_agg(to_jsonb(p) - 'subscription_id' ORDER BY p.paused_from), '[]'::jsonb)
+				FROM subscription_pauses p
+				WHERE p.subscription_id = s.id
+			),
+			'price_changes', (
+				SELECT COALESCE(jsonb_agg(to_jsonb(p) - 'subscription_id' ORDER BY p.effective_from), '[]'::jsonb)
+				FROM subscription_prices p
+				WHERE p.subscription_id = s.id
+			)
+		)`
+
+type txKey struct{}
+
+// InTx runs fn in one transaction. Repository calls made with the context passed to fn join it,
+// and a change that fails inside rolls back only itself.
+func (r *Repository) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction started by InTx, if ctx carries one, or the pool.
+func (r *Repository) conn(ctx context.Context) dbExecutor {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return r.db
+}
+
+// inTx runs fn in a transaction that is committed only if fn succeeds. Inside InTx it runs in a
+// savepoint of the outer transaction.
+func (r *Repository) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// audited applies change to a subscription, bumps its version and records the change in the audit
+// trail, in one transaction. The subscription is locked first; it must be in the trash when trashed
+// is set and live otherwise, and a non-zero version must match the stored one.
+func (r *Repository) audited(
+	ctx context.Context, id string, action domain.EventAction, trashed bool, version int64, change func(tx pgx.Tx) error,
+) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var before []byte
+		var deleted bool
+		var current int64
+		err := tx.QueryRow(ctx, `
+			SELECT `+subscriptionSnapshot+`, s.deleted_at IS NOT NULL, s.version
+			FROM subscriptions s
+			WHERE s.id = $1
+			FOR UPDATE
+		`, id).Scan(&before, &deleted, &current)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && deleted != trashed) {
+			return domain.ErrSubscriptionNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("lock subscription: %w", err)
+		}
+		if version != 0 && version != current {
+			return domain.ErrPreconditionFailed
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE subscriptions SET version = version + 1 WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("bump subscription version: %w", err)
+		}
+
+		return recordEvent(ctx, tx, id, action, before)
+	})
+}
+
+// recordEvent appends an event whose after snapshot is the current state of the subscription.
+func recordEvent(ctx context.Context, tx pgx.Tx, id string, action domain.EventAction, before []byte) error {
+	query := `
+		INSERT INTO subscription_events (subscription_id, action, before, after, actor, request_id)
+		SELECT s.id, $2, $3::jsonb, ` + subscriptionSnapshot + `, $4, $5
+		FROM subscriptions s
+		WHERE s.id = $1
+	`
+
+	_, err := tx.Exec(ctx, query, id, action, before, domain.ActorFromContext(ctx), domain.RequestIDFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("record %s event: %w", action, err)
+	}
+	return nil
+}
+
+// ListEvents returns the audit trail of a subscription, oldest first.
+func (r *Repository) ListEvents(ctx context.Context, subscriptionID string) ([]domain.SubscriptionEvent, error) {
+	query := `
+		SELECT id, subscription_id::text, action, before, after, actor, request_id, created_at
+		FROM subscription_events
+		WHERE subscription_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("list subscription events: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.SubscriptionEvent, 0)
+	for rows.Next() {
+		var event domain.SubscriptionEvent
+		if err := rows.Scan(
+			&event.ID, &event.SubscriptionID, &event.Action, &event.Before, &event.After, &event.Actor, &event.RequestID, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan subscription event: %w", err)
+		}
+		result = append(result, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subscription events: %w", err)
+	}
+
+	return result, nil
+}
@@ -7,8 +7,14 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-type dbExecutor interface {
+// queryer is implemented by both the pool and a transaction.
+type queryer interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
+
+type dbExecutor interface {
+	queryer
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
@@ -0,0 +1,96 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+// pausedBetween is an SQL condition true when the subscription with the given id is paused on
+// every day from first to last, both SQL date expressions.
+func pausedBetween(id, first, last string) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM subscription_pauses sp
+		WHERE sp.subscription_id = %s
+			AND sp.paused_from <= %s
+			AND (sp.resumed_from IS NULL OR sp.resumed_from > %s)
+	)`, id, first, last)
+}
+
+// Pause opens a pause from the given day.
+func (r *Repository) Pause(ctx context.Context, id string, from string) error {
+	query := `
+		INSERT INTO subscription_pauses (subscription_id, paused_from)
+		VALUES ($1, to_date($2, 'YYYY-MM-DD'))
+	`
+
+	return r.audited(ctx, id, domain.EventPause, false, 0, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, id, from); err != nil {
+			return fmt.Errorf("pause subscription: %w", err)
+		}
+		return nil
+	})
+}
+
+// Resume closes the open pause of a subscription; charges start again on the given day.
+func (r *Repository) Resume(ctx context.Context, id string, from string) error {
+	query := `
+		UPDATE subscription_pauses
+		SET resumed_from = to_date($2, 'YYYY-MM-DD')
+		WHERE subscription_id = $1 AND resumed_from IS NULL
+	`
+
+	return r.audited(ctx, id, domain.EventResume, false, 0, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, id, from)
+		if err != nil {
+			return fmt.Errorf("resume subscription: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrSubscriptionNotPaused
+		}
+		return nil
+	})
+}
+
+func (r *Repository) listPauses(ctx context.Context, id string) ([]domain.Pause, error) {
+	query := `
+		SELECT
+			CASE
+				WHEN EXTRACT(DAY FROM paused_from) = 1 THEN to_char(paused_from, 'MM-YYYY')
+				ELSE to_char(paused_from, 'YYYY-MM-DD')
+			END,
+			CASE
+				WHEN resumed_from IS NULL THEN NULL
+				WHEN EXTRACT(DAY FROM resumed_from) = 1 THEN to_char(resumed_from, 'MM-YYYY')
+				ELSE to_char(resumed_from, 'YYYY-MM-DD')
+			END
+		FROM subscription_pauses
+		WHERE subscription_id = $1
+		ORDER BY paused_from
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("list pauses: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.Pause, 0)
+	for rows.Next() {
+		var pause domain.Pause
+		if err := rows.Scan(&pause.PausedFrom, &pause.ResumedFrom); err != nil {
+			return nil, fmt.Errorf("scan pause: %w", err)
+		}
+		result = append(result, pause)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pauses: %w", err)
+	}
+
+	return result, nil
+}
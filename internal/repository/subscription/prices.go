@@ -0,0 +1,64 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+// AddPriceChange records a price change, replacing a change already recorded for the same day.
+func (r *Repository) AddPriceChange(ctx context.Context, change domain.PriceChange) error {
+	query := `
+		INSERT INTO subscription_prices (subscription_id, price, effective_from)
+		VALUES ($1, $2, to_date($3, 'YYYY-MM-DD'))
+		ON CONFLICT (subscription_id, effective_from)
+		DO UPDATE SET price = EXCLUDED.price, created_at = NOW()
+	`
+
+	return r.audited(ctx, change.SubscriptionID, domain.EventPriceChange, false, 0, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, change.SubscriptionID, change.Price, change.EffectiveFrom); err != nil {
+			return fmt.Errorf("add price change: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListPriceChanges returns the price changes of a subscription, oldest first.
+func (r *Repository) ListPriceChanges(ctx context.Context, subscriptionID string) ([]domain.PriceChange, error) {
+	query := `
+		SELECT
+			subscription_id,
+			price,
+			CASE
+				WHEN EXTRACT(DAY FROM effective_from) = 1 THEN to_char(effective_from, 'MM-YYYY')
+				ELSE to_char(effective_from, 'YYYY-MM-DD')
+			END
+		FROM subscription_prices
+		WHERE subscription_id = $1
+		ORDER BY effective_from
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("list price changes: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.PriceChange, 0)
+	for rows.Next() {
+		var change domain.PriceChange
+		if err := rows.Scan(&change.SubscriptionID, &change.Price, &change.EffectiveFrom); err != nil {
+			return nil, fmt.Errorf("scan price change: %w", err)
+		}
+		result = append(result, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate price changes: %w", err)
+	}
+
+	return result, nil
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -21,49 +23,125 @@ func New(db dbExecutor) *Repository {
 	return &Repository{db: db}
 }
 
+// subscriptionStatus computes domain.SubscriptionStatus for a row of subscriptions. A cancellation
+// takes effect at the end date, so until then the subscription is still charged and reported as
+// active or paused.
+var subscriptionStatus = `CASE
+				WHEN end_date < CURRENT_DATE AND cancelled_at IS NOT NULL THEN 'cancelled'
+				WHEN end_date < CURRENT_DATE THEN 'expired'
+				WHEN ` + pausedBetween("subscriptions.id", "CURRENT_DATE", "CURRENT_DATE") + ` THEN 'paused'
+				ELSE 'active'
+			END`
+
+// subscriptionColumns is the select list read by scanSubscription. Dates covering whole months
+// are rendered as MM-YYYY, other dates as YYYY-MM-DD.
+var subscriptionColumns = `
+			id,
+			service_name,
+			price,
+			currency,
+			billing_period,
+			user_id,
+			CASE
+				WHEN EXTRACT(DAY FROM start_date) = 1 THEN to_char(start_date, 'MM-YYYY')
+				ELSE to_char(start_date, 'YYYY-MM-DD')
+			END,
+			CASE
+				WHEN end_date IS NULL THEN NULL
+				WHEN end_date = (date_trunc('month', end_date) + interval '1 month - 1 day')::date THEN to_char(end_date, 'MM-YYYY')
+				ELSE to_char(end_date, 'YYYY-MM-DD')
+			END,
+			CASE
+				WHEN trial_end IS NULL THEN NULL
+				WHEN trial_end = (date_trunc('month', trial_end) + interval '1 month - 1 day')::date THEN to_char(trial_end, 'MM-YYYY')
+				ELSE to_char(trial_end, 'YYYY-MM-DD')
+			END,
+			` + subscriptionStatus + `,
+			cancelled_at,
+			COALESCE(cancel_reason, ''),
+			deleted_at,
+			version`
+
+// scanSubscription scans subscriptionColumns followed by any extra destinations.
+func scanSubscription(row pgx.Row, extra ...any) (domain.Subscription, error) {
+	var sub domain.Subscription
+	var parsedID uuid.UUID
+	var userID uuid.UUID
+	var endDate sql.NullString
+	var trialEnd sql.NullString
+	var cancelledAt sql.NullTime
+	var deletedAt sql.NullTime
+
+	dest := append([]any{
+		&parsedID,
+		&sub.ServiceName,
+		&sub.Price,
+		&sub.Currency,
+		&sub.BillingPeriod,
+		&userID,
+		&sub.StartDate,
+		&endDate,
+		&trialEnd,
+		&sub.Status,
+		&cancelledAt,
+		&sub.CancelReason,
+		&deletedAt,
+		&sub.Version,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	sub.ID = parsedID.String()
+	sub.UserID = userID.String()
+	if endDate.Valid {
+		sub.EndDate = &endDate.String
+	}
+	if trialEnd.Valid {
+		sub.TrialEnd = &trialEnd.String
+	}
+	if cancelledAt.Valid {
+		sub.CancelledAt = &cancelledAt.Time
+	}
+	if deletedAt.Valid {
+		sub.DeletedAt = &deletedAt.Time
+	}
+
+	return sub, nil
+}
+
 func (r *Repository) Create(ctx context.Context, sub domain.Subscription) (string, error) {
 	query := `
-		INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, to_date($4, 'MM-YYYY'), to_date($5, 'MM-YYYY'))
+		INSERT INTO subscriptions (service_name, price, currency, billing_period, user_id, start_date, end_date, trial_end)
+		VALUES ($1, $2, $3, $4, $5, to_date($6, 'YYYY-MM-DD'), to_date($7, 'YYYY-MM-DD'), to_date($8, 'YYYY-MM-DD'))
 		RETURNING id
 	`
 
 	var id uuid.UUID
-	err := r.db.QueryRow(ctx, query, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate).Scan(&id)
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx, query, sub.ServiceName, sub.Price, sub.Currency, sub.BillingPeriod, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd,
+		).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("create subscription: %w", err)
+		}
+
+		return recordEvent(ctx, tx, id.String(), domain.EventCreate, nil)
+	})
 	if err != nil {
-		return "", fmt.Errorf("create subscription: %w", err)
+		return "", err
 	}
 
 	return id.String(), nil
 }
 
 func (r *Repository) GetByID(ctx context.Context, id string) (domain.Subscription, error) {
-	query := `
-		SELECT
-			id,
-			service_name,
-			price,
-			user_id,
-			to_char(start_date, 'MM-YYYY'),
-			CASE WHEN end_date IS NULL THEN NULL ELSE to_char(end_date, 'MM-YYYY') END
+	query := `SELECT` + subscriptionColumns + `
 		FROM subscriptions
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	var sub domain.Subscription
-	var parsedID uuid.UUID
-	var userID uuid.UUID
-	var startDate string
-	var endDate sql.NullString
-
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&parsedID,
-		&sub.ServiceName,
-		&sub.Price,
-		&userID,
-		&startDate,
-		&endDate,
-	)
+	sub, err := scanSubscription(r.conn(ctx).QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Subscription{}, domain.ErrSubscriptionNotFound
@@ -71,40 +149,85 @@ func (r *Repository) GetByID(ctx context.Context, id string) (domain.Subscriptio
 		return domain.Subscription{}, fmt.Errorf("get subscription by id: %w", err)
 	}
 
-	sub.ID = parsedID.String()
-	sub.UserID = userID.String()
-	sub.StartDate = startDate
-	if endDate.Valid {
-		sub.EndDate = &endDate.String
+	sub.Pauses, err = r.listPauses(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
 	}
 
 	return sub, nil
 }
 
-func (r *Repository) List(ctx context.Context, userID string, serviceName string) ([]domain.Subscription, error) {
+// Owner returns the user a subscription belongs to, whether it is live, in the trash or purged.
+// A purged subscription's user is taken from its last audit snapshot.
+func (r *Repository) Owner(ctx context.Context, id string) (string, error) {
+	var owner *string
+	err := r.conn(ctx).QueryRow(ctx, `
+		SELECT COALESCE(
+			(SELECT user_id::text FROM subscriptions WHERE id = $1),
+			(
+				SELECT COALESCE(after, before)->>'user_id'
+				FROM subscription_events
+				WHERE subscription_id = $1
+				ORDER BY id DESC
+				LIMIT 1
+			)
+		)
+	`, id).Scan(&owner)
+	if err != nil {
+		return "", fmt.Errorf("get subscription owner: %w", err)
+	}
+	if owner == nil {
+		return "", domain.ErrSubscriptionNotFound
+	}
+
+	return *owner, nil
+}
+
+// sortColumn describes how a sort field is ordered in SQL, how its cursor key is rendered
+// and how that key is cast back when it is used as a keyset bound.
+type sortColumn struct {
+	column string
+	key    string
+	cast   string
+}
+
+var sortColumns = map[domain.SortField]sortColumn{
+	domain.SortByCreatedAt: {
+		column: "created_at",
+		key:    `to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`,
+		cast:   "timestamptz",
+	},
+	domain.SortByPrice:       {column: "price", key: "price::text", cast: "integer"},
+	domain.SortByStartDate:   {column: "start_date", key: "to_char(start_date, 'YYYY-MM-DD')", cast: "date"},
+	domain.SortByServiceName: {column: "service_name", key: "service_name", cast: "text"},
+}
+
+func (r *Repository) List(ctx context.Context, filter domain.ListFilter, page domain.Pagination) (domain.SubscriptionPage, error) {
+	sortCol, ok := sortColumns[page.Sort.Field]
+	if !ok {
+		return domain.SubscriptionPage{}, fmt.Errorf("list subscriptions: unsupported sort field %q", page.Sort.Field)
+	}
+
 	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(`
-		SELECT
-			id,
-			service_name,
-			price,
-			user_id,
-			TO_CHAR(start_date, 'MM-YYYY'),
-			CASE WHEN end_date IS NULL THEN NULL ELSE to_char(end_date, 'MM-YYYY') END
+	fmt.Fprintf(&queryBuilder, `
+		SELECT%s,
+			%s
 		FROM subscriptions
-`)
+`, subscriptionColumns, sortCol.key)
 
-	args := make([]any, 0, 2)
-	conditions := make([]string, 0, 2)
+	conditions, args := listConditions(filter)
 
-	if userID != "" {
-		args = append(args, userID)
-		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	direction, comparison := "ASC", ">"
+	if page.Sort.Desc {
+		direction, comparison = "DESC", "<"
 	}
 
-	if serviceName != "" {
-		args = append(args, serviceName)
-		conditions = append(conditions, fmt.Sprintf("service_name = $%d", len(args)))
+	if page.After != nil {
+		args = append(args, page.After.Key, page.After.ID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(%s, id) %s ($%d::%s, $%d::uuid)",
+			sortCol.column, comparison, len(args)-1, sortCol.cast, len(args),
+		))
 	}
 
 	if len(conditions) > 0 {
@@ -112,130 +235,510 @@ func (r *Repository) List(ctx context.Context, userID string, serviceName string
 		queryBuilder.WriteString(strings.Join(conditions, " AND "))
 	}
 
-	queryBuilder.WriteString(" ORDER BY created_at DESC")
+	// One extra row tells whether there is a next page.
+	args = append(args, page.Limit+1)
+	fmt.Fprintf(&queryBuilder, " ORDER BY %s %s, id %s LIMIT $%d", sortCol.column, direction, direction, len(args))
 
-	rows, err := r.db.Query(ctx, queryBuilder.String(), args...)
+	rows, err := r.conn(ctx).Query(ctx, queryBuilder.String(), args...)
 	if err != nil {
-		return nil, fmt.Errorf("list subscriptions: %w", err)
+		return domain.SubscriptionPage{}, fmt.Errorf("list subscriptions: %w", err)
 	}
 	defer rows.Close()
 
-	result := make([]domain.Subscription, 0)
+	result := domain.SubscriptionPage{Items: make([]domain.Subscription, 0, page.Limit)}
+	var lastKey string
 	for rows.Next() {
-		var sub domain.Subscription
-		var parsedID uuid.UUID
-		var parsedUserID uuid.UUID
-		var startDate string
-		var endDate sql.NullString
-
-		if err := rows.Scan(
-			&parsedID,
-			&sub.ServiceName,
-			&sub.Price,
-			&parsedUserID,
-			&startDate,
-			&endDate,
-		); err != nil {
-			return nil, fmt.Errorf("scan listed subscription: %w", err)
+		var sortKey string
+		sub, err := scanSubscription(rows, &sortKey)
+		if err != nil {
+			return domain.SubscriptionPage{}, fmt.Errorf("scan listed subscription: %w", err)
 		}
 
-		sub.ID = parsedID.String()
-		sub.UserID = parsedUserID.String()
-		sub.StartDate = startDate
-		if endDate.Valid {
-			sub.EndDate = &endDate.String
+		if len(result.Items) == page.Limit {
+			last := result.Items[len(result.Items)-1]
+			result.NextCursor = domain.Cursor{Sort: page.Sort.String(), Key: lastKey, ID: last.ID}.Encode()
+			break
 		}
 
-		result = append(result, sub)
+		result.Items = append(result.Items, sub)
+		lastKey = sortKey
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate listed subscriptions: %w", err)
+		return domain.SubscriptionPage{}, fmt.Errorf("iterate listed subscriptions: %w", err)
 	}
 
 	return result, nil
 }
 
+// Export streams the subscriptions matching filter, oldest first. Rows are read off the pgx cursor
+// as the sequence is consumed, so the result set is never held in memory.
+func (r *Repository) Export(ctx context.Context, filter domain.ListFilter) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		conditions, args := listConditions(filter)
+		query := `SELECT` + subscriptionColumns + `
+			FROM subscriptions
+			WHERE ` + strings.Join(conditions, " AND ") + `
+			ORDER BY created_at, id
+		`
+
+		rows, err := r.conn(ctx).Query(ctx, query, args...)
+		if err != nil {
+			yield(domain.Subscription{}, fmt.Errorf("export subscriptions: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			sub, err := scanSubscription(rows)
+			if err != nil {
+				yield(domain.Subscription{}, fmt.Errorf("scan exported subscription: %w", err))
+				return
+			}
+			if !yield(sub, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(domain.Subscription{}, fmt.Errorf("iterate exported subscriptions: %w", err))
+		}
+	}
+}
+
+// monthEnd is the last day of the MM-YYYY month bound to the first format argument.
+const monthEnd = "(to_date($%[1]d, 'MM-YYYY') + interval '1 month - 1 day')::date"
+
+func listConditions(filter domain.ListFilter) ([]string, []any) {
+	args := make([]any, 0, 8)
+	conditions := make([]string, 0, 8)
+
+	add := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.Deleted {
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if filter.UserID != "" {
+		add("user_id = $%d", filter.UserID)
+	}
+
+	if filter.OwnerID != "" {
+		add("user_id = $%d", filter.OwnerID)
+	}
+
+	if filter.ServiceName != "" {
+		add("service_name = $%d", filter.ServiceName)
+	}
+
+	if filter.Search != "" {
+		// Served by the trigram index on service_name.
+		add("service_name ILIKE '%%' || $%d::text || '%%'", escapeLike(filter.Search))
+	}
+
+	if filter.MinPrice != nil {
+		add("price >= $%d", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		add("price <= $%d", *filter.MaxPrice)
+	}
+
+	if filter.ActiveIn != "" {
+		add("start_date <= "+monthEnd+" AND (end_date IS NULL OR end_date >= to_date($%[1]d, 'MM-YYYY'))"+
+			" AND NOT "+pausedBetween("subscriptions.id", "to_date($%[1]d, 'MM-YYYY')", monthEnd), filter.ActiveIn)
+	}
+
+	if filter.StartFrom != "" {
+		add("start_date >= to_date($%d, 'MM-YYYY')", filter.StartFrom)
+	}
+
+	if filter.StartTo != "" {
+		add("start_date <= "+monthEnd, filter.StartTo)
+	}
+
+	if filter.EndFrom != "" {
+		add("end_date >= to_date($%d, 'MM-YYYY')", filter.EndFrom)
+	}
+
+	if filter.EndTo != "" {
+		add("end_date <= "+monthEnd, filter.EndTo)
+	}
+
+	if filter.OpenEnded {
+		conditions = append(conditions, "end_date IS NULL")
+	}
+
+	if filter.Status != "" {
+		add(subscriptionStatus+" = $%d", string(filter.Status))
+	}
+
+	if filter.TrialEndsWithin != nil {
+		add("trial_end BETWEEN CURRENT_DATE AND CURRENT_DATE + $%d::integer", *filter.TrialEndsWithin)
+	}
+
+	return conditions, args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
 func (r *Repository) Update(ctx context.Context, sub domain.Subscription) error {
 	query := `
 		UPDATE subscriptions
 		SET
 			service_name = $2,
 			price = $3,
-			user_id = $4,
-			start_date = to_date($5, 'MM-YYYY'),
-			end_date = to_date($6, 'MM-YYYY')
+			currency = $4,
+			billing_period = $5,
+			user_id = $6,
+			start_date = to_date($7, 'YYYY-MM-DD'),
+			end_date = to_date($8, 'YYYY-MM-DD'),
+			trial_end = to_date($9, 'YYYY-MM-DD')
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, sub.ID, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate)
-	if err != nil {
-		return fmt.Errorf("update subscription: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return domain.ErrSubscriptionNotFound
-	}
-
-	return nil
+	return r.audited(ctx, sub.ID, domain.EventUpdate, false, sub.Version, func(tx pgx.Tx) error {
+		_, err := tx.Exec(
+			ctx, query, sub.ID, sub.ServiceName, sub.Price, sub.Currency, sub.BillingPeriod, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd,
+		)
+		if err != nil {
+			return fmt.Errorf("update subscription: %w", err)
+		}
+		return nil
+	})
 }
 
-func (r *Repository) Delete(ctx context.Context, id string) error {
-	result, err := r.db.Exec(ctx, `DELETE FROM subscriptions WHERE id = $1`, id)
-	if err != nil {
-		return fmt.Errorf("delete subscription: %w", err)
-	}
+// Delete moves a subscription to the trash. It stays there until restored or purged.
+// A non-zero version must match the stored one.
+func (r *Repository) Delete(ctx context.Context, id string, version int64) error {
+	return r.audited(ctx, id, domain.EventDelete, false, version, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE subscriptions SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("delete subscription: %w", err)
+		}
+		return nil
+	})
+}
 
-	if result.RowsAffected() == 0 {
-		return domain.ErrSubscriptionNotFound
+// billingStep is the interval between two charges of a subscription.
+const billingStep = `CASE s.billing_period
+					WHEN 'weekly' THEN interval '1 week'
+					WHEN 'quarterly' THEN interval '3 months'
+					WHEN 'yearly' THEN interval '1 year'
+					ELSE interval '1 month'
+				END`
+
+// billingSteps is an upper bound of the number of billing steps between start_date and charged_until.
+const billingSteps = `CASE s.billing_period
+					WHEN 'weekly' THEN (l.charged_until - s.start_date) / 7
+					ELSE (
+						(date_part('year', l.charged_until)::int * 12 + date_part('month', l.charged_until)::int) -
+						(date_part('year', s.start_date)::int * 12 + date_part('month', s.start_date)::int)
+					) / CASE s.billing_period WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END
+				END`
+
+// firstBillingStep is the first billing step on or after from_date. Charges before the period are
+// never generated, so the work per subscription does not grow with its age. A monthly-based
+// step always lands in the month it is counted for, and from_date is the first of a month.
+const firstBillingStep = `GREATEST(0, CASE s.billing_period
+					WHEN 'weekly' THEN (b.from_date - s.start_date + 6) / 7
+					ELSE (
+						(date_part('year', b.from_date)::int * 12 + date_part('month', b.from_date)::int) -
+						(date_part('year', s.start_date)::int * 12 + date_part('month', s.start_date)::int) +
+						CASE s.billing_period WHEN 'quarterly' THEN 2 WHEN 'yearly' THEN 11 ELSE 0 END
+					) / CASE s.billing_period WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END
+				END)`
+
+// chargesCTE builds the CTEs every aggregate over a period reads from, so totals, breakdowns
+// and timeseries always agree:
+//   - "bounds": the period, from the first day of From to the last day of To;
+//   - "scoped": filtered subscriptions active at some day of the period;
+//   - "target": the currency of the result, filter.Currency or, when none was requested, the
+//     currency every scoped subscription is billed in, falling back to domain.DefaultCurrency
+//     when they differ;
+//   - "charges": one row per charge falling inside the period. The n-th charge is on
+//     start_date + n billing periods, as long as the subscription is active, past its trial
+//     and not paused that day. Only the steps from firstBillingStep on are generated.
+//
+// A charge costs the price in effect on its day (see domain.PriceChange). Unless native is set,
+// amounts are converted to the target currency and rounded per charge; a charge without a known
+// exchange rate has a NULL amount (see missingRates). Native amounts stay in the subscription's
+// own currency and need no rate. With daily proration a charge whose billing period outlives
+// the end date only costs the share of the period's days still active.
+func chargesCTE(filter domain.TotalFilter, native bool) (string, []any) {
+	rate, rateJoin := "1", ""
+	if !native {
+		rate = "CASE WHEN s.currency = t.currency THEN 1 ELSE er.rate END"
+		rateJoin = `CROSS JOIN target t
+			LEFT JOIN exchange_rates er ON er.base_currency = s.currency AND er.quote_currency = t.currency`
 	}
 
-	return nil
-}
-
-func (r *Repository) Total(ctx context.Context, filter domain.Subscription) (int64, error) {
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(`
 		WITH bounds AS (
-				SELECT
-						to_date($1, 'MM-YYYY') AS from_date,
-						to_date($2, 'MM-YYYY') AS to_date
-		)
-		SELECT COALESCE(SUM(
-				s.price * (
-				(
-					(
-						EXTRACT(YEAR FROM LEAST(COALESCE(s.end_date, b.to_date), b.to_date)) * 12 +
-						EXTRACT(MONTH FROM LEAST(COALESCE(s.end_date, b.to_date), b.to_date))
-					) - (
-						EXTRACT(YEAR FROM GREATEST(s.start_date, b.from_date)) * 12 +
-						EXTRACT(MONTH FROM GREATEST(s.start_date, b.from_date))
-					) + 1
-				)
-			)
-		), 0)::bigint
-		FROM subscriptions s
-		CROSS JOIN bounds b
-		WHERE s.start_date <= b.to_date
-			AND COALESCE(s.end_date, b.to_date) >= b.from_date
+			SELECT
+				to_date($1, 'MM-YYYY') AS from_date,
+				(to_date($2, 'MM-YYYY') + interval '1 month - 1 day')::date AS to_date,
+				NULLIF($3::text, '') AS currency,
+				$4::text = 'daily' AS prorate
+		),
+		scoped AS (
+			SELECT s.*
+			FROM subscriptions s
+			CROSS JOIN bounds b
+			WHERE s.deleted_at IS NULL
+				AND s.start_date <= b.to_date
+				AND COALESCE(s.end_date, b.to_date) >= b.from_date
 	`)
 
-	args := []any{filter.StartDate, *filter.EndDate}
+	args := []any{filter.From, filter.To, filter.Currency, string(filter.Proration), domain.DefaultCurrency}
 
 	if filter.UserID != "" {
 		args = append(args, filter.UserID)
 		fmt.Fprintf(&queryBuilder, " AND s.user_id = $%d", len(args))
 	}
 
+	if filter.OwnerID != "" {
+		args = append(args, filter.OwnerID)
+		fmt.Fprintf(&queryBuilder, " AND s.user_id = $%d", len(args))
+	}
+
 	if filter.ServiceName != "" {
 		args = append(args, filter.ServiceName)
 		fmt.Fprintf(&queryBuilder, " AND s.service_name = $%d", len(args))
 	}
 
+	queryBuilder.WriteString(`
+		),
+		target AS (
+			SELECT COALESCE(
+				b.currency,
+				CASE WHEN COUNT(DISTINCT s.currency) = 1 THEN MIN(s.currency) END,
+				$5::text
+			) AS currency
+			FROM bounds b
+			LEFT JOIN scoped s ON true
+			GROUP BY b.currency
+		),
+		charges AS (
+			SELECT
+				s.id,
+				s.user_id,
+				s.service_name,
+				s.currency,
+				date_trunc('month', c.charged_at)::date AS month,
+				round(
+					COALESCE(sp.price, s.price)
+					* ` + rate + `
+					* CASE
+						WHEN b.prorate AND s.end_date < c.next_charge_at
+							THEN (s.end_date - c.charged_at + 1)::numeric / (c.next_charge_at - c.charged_at)
+						ELSE 1
+					END
+				)::bigint AS amount
+			FROM scoped s
+			CROSS JOIN bounds b
+			` + rateJoin + `
+			CROSS JOIN LATERAL (
+				SELECT LEAST(COALESCE(s.end_date, b.to_date), b.to_date) AS charged_until
+			) l
+			CROSS JOIN LATERAL generate_series(` + firstBillingStep + `, ` + billingSteps + `) AS n
+			CROSS JOIN LATERAL (
+				SELECT
+					(s.start_date + n * ` + billingStep + `)::date AS charged_at,
+					(s.start_date + (n + 1) * ` + billingStep + `)::date AS next_charge_at
+			) c
+			LEFT JOIN LATERAL (
+				SELECT p.price
+				FROM subscription_prices p
+				WHERE p.subscription_id = s.id AND p.effective_from <= c.charged_at
+				ORDER BY p.effective_from DESC
+				LIMIT 1
+			) sp ON true
+			WHERE c.charged_at >= b.from_date
+				AND c.charged_at <= l.charged_until
+				AND (s.trial_end IS NULL OR c.charged_at > s.trial_end)
+				AND NOT ` + pausedBetween("s.id", "c.charged_at", "c.charged_at") + `
+		)
+	`)
+
+	return queryBuilder.String(), args
+}
+
+// missingRates aggregates the currencies of the charges that could not be converted. Each
+// aggregate query selects it next to its amounts so a missing rate costs no extra round trip.
+const missingRates = `array_agg(DISTINCT currency) FILTER (WHERE amount IS NULL)`
+
+// missingRateError fails with domain.ErrMissingExchangeRate if any charge could not be converted.
+func missingRateError(missing []string, currency string) error {
+	if len(missing) == 0 {
+		return nil
+	}
+
+	slices.Sort(missing)
+	missing = slices.Compact(missing)
+	return fmt.Errorf("%w: %s to %s", domain.ErrMissingExchangeRate, strings.Join(missing, ", "), currency)
+}
+
+// Total sums the charges of the period and reports the currency they were summed in.
+func (r *Repository) Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error) {
+	cte, args := chargesCTE(filter, false)
+	query := cte + `SELECT COALESCE(SUM(amount), 0)::bigint, ` + missingRates + `, (SELECT currency FROM target) FROM charges`
+
 	var total int64
-	if err := r.db.QueryRow(ctx, queryBuilder.String(), args...).Scan(&total); err != nil {
-		return 0, fmt.Errorf("calculate subscriptions total: %w", err)
+	var missing []string
+	var currency string
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&total, &missing, &currency); err != nil {
+		return 0, "", fmt.Errorf("calculate subscriptions total: %w", err)
+	}
+	if err := missingRateError(missing, currency); err != nil {
+		return 0, "", err
+	}
+
+	return total, currency, nil
+}
+
+var bucketKeys = map[domain.TotalGroupBy]struct {
+	key     string
+	groupBy string
+}{
+	domain.GroupByServiceName: {key: "service_name", groupBy: "service_name"},
+	domain.GroupByUserID:      {key: "user_id::text", groupBy: "user_id"},
+	domain.GroupByMonth:       {key: "to_char(month, 'MM-YYYY')", groupBy: "month"},
+	domain.GroupByCurrency:    {key: "currency", groupBy: "currency"},
+}
+
+// TotalBreakdown groups the charges of the period and reports the currency of the buckets. A
+// breakdown by currency keeps every bucket in its own currency, so it needs no exchange rate and
+// reports no common currency.
+func (r *Repository) TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy domain.TotalGroupBy) ([]domain.TotalBucket, string, error) {
+	bucket, ok := bucketKeys[groupBy]
+	if !ok {
+		return nil, "", fmt.Errorf("calculate subscriptions breakdown: unsupported group by %q", groupBy)
+	}
+	native := groupBy == domain.GroupByCurrency
+
+	// The buckets are joined to the target so its currency is known even without any charge.
+	cte, args := chargesCTE(filter, native)
+	query := cte + fmt.Sprintf(`
+		SELECT t.currency, g.key, g.amount, g.count, g.missing
+		FROM target t
+		LEFT JOIN (
+			SELECT
+				%s AS key,
+				%s AS sort,
+				COALESCE(SUM(amount), 0)::bigint AS amount,
+				COUNT(DISTINCT id) AS count,
+				`+missingRates+` AS missing
+			FROM charges
+			GROUP BY %s
+		) g ON true
+		ORDER BY g.sort
+	`, bucket.key, bucket.groupBy, bucket.groupBy)
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("calculate subscriptions breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.TotalBucket, 0)
+	var currency string
+	var missing []string
+	for rows.Next() {
+		var key *string
+		var amount, count *int64
+		var bucketMissing []string
+		if err := rows.Scan(&currency, &key, &amount, &count, &bucketMissing); err != nil {
+			return nil, "", fmt.Errorf("scan subscriptions breakdown: %w", err)
+		}
+		if key == nil {
+			continue
+		}
+		result = append(result, domain.TotalBucket{Key: *key, Amount: *amount, Count: *count})
+		missing = append(missing, bucketMissing...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterate subscriptions breakdown: %w", err)
+	}
+	if native {
+		return result, "", nil
+	}
+	if err := missingRateError(missing, currency); err != nil {
+		return nil, "", err
+	}
+
+	return result, currency, nil
+}
+
+// Timeseries reports the spend and subscription movement of every month of the period, and the
+// currency the spend is in.
+func (r *Repository) Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error) {
+	cte, args := chargesCTE(filter, false)
+	query := cte + `,
+		months AS (
+			SELECT m::date AS month
+			FROM bounds b
+			CROSS JOIN LATERAL generate_series(b.from_date, b.to_date, interval '1 month') AS m
+		),
+		monthly_charges AS (
+			SELECT month, SUM(amount) AS amount, ` + missingRates + ` AS missing
+			FROM charges
+			GROUP BY month
+		)
+		SELECT
+			to_char(mo.month, 'MM-YYYY'),
+			COALESCE(mc.amount, 0)::bigint,
+			mc.missing,
+			(SELECT currency FROM target),
+			COUNT(s.id) FILTER (
+				WHERE NOT ` + pausedBetween("s.id", "mo.month", "(mo.month + interval '1 month - 1 day')::date") + `
+			),
+			COUNT(s.id) FILTER (WHERE date_trunc('month', s.start_date) = mo.month),
+			COUNT(s.id) FILTER (WHERE date_trunc('month', s.end_date) = mo.month)
+		FROM months mo
+		LEFT JOIN monthly_charges mc ON mc.month = mo.month
+		LEFT JOIN scoped s
+			ON s.start_date <= (mo.month + interval '1 month - 1 day')::date
+			AND COALESCE(s.end_date, mo.month) >= mo.month
+		GROUP BY mo.month, mc.amount, mc.missing
+		ORDER BY mo.month
+	`
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("calculate subscriptions timeseries: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]domain.TimeseriesPoint, 0)
+	var currency string
+	var missing []string
+	for rows.Next() {
+		var p domain.TimeseriesPoint
+		var monthMissing []string
+		if err := rows.Scan(&p.Month, &p.Amount, &monthMissing, &currency, &p.Active, &p.Started, &p.Ended); err != nil {
+			return nil, "", fmt.Errorf("scan subscriptions timeseries: %w", err)
+		}
+		result = append(result, p)
+		missing = append(missing, monthMissing...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterate subscriptions timeseries: %w", err)
+	}
+	if err := missingRateError(missing, currency); err != nil {
+		return nil, "", err
 	}
 
-	return total, nil
+	return result, currency, nil
 }
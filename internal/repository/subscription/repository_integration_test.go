@@ -5,9 +5,12 @@ package subscription_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -47,7 +50,7 @@ func TestMain(m *testing.M) {
 
 func cleanupDB(t *testing.T) {
 	t.Helper()
-	_, err := testPool.Exec(context.Background(), "TRUNCATE TABLE subscriptions")
+	_, err := testPool.Exec(context.Background(), "TRUNCATE TABLE subscriptions, subscription_prices, subscription_pauses, subscription_events, exchange_rates")
 	require.NoError(t, err)
 }
 
@@ -57,10 +60,12 @@ func TestRepositoryCreateGet(t *testing.T) {
 	repo := repository.New(testPool)
 	userID := uuid.NewString()
 	sub := domain.Subscription{
-		ServiceName: "Netflix",
-		Price:       500,
-		UserID:      userID,
-		StartDate:   "07-2025",
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
 	}
 
 	id, err := repo.Create(context.Background(), sub)
@@ -73,6 +78,7 @@ func TestRepositoryCreateGet(t *testing.T) {
 	require.Equal(t, sub.Price, got.Price)
 	require.Equal(t, sub.UserID, got.UserID)
 	require.Equal(t, sub.StartDate, got.StartDate)
+	require.Equal(t, domain.DefaultCurrency, got.Currency)
 	require.Nil(t, got.EndDate)
 }
 
@@ -82,23 +88,27 @@ func TestRepositoryUpdate(t *testing.T) {
 	repo := repository.New(testPool)
 	userID := uuid.NewString()
 	sub := domain.Subscription{
-		ServiceName: "Netflix",
-		Price:       500,
-		UserID:      userID,
-		StartDate:   "07-2025",
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
 	}
 
 	id, err := repo.Create(context.Background(), sub)
 	require.NoError(t, err)
 
-	end := "12-2025"
+	end := "2025-12-31"
 	update := domain.Subscription{
-		ID:          id,
-		ServiceName: "HBO",
-		Price:       700,
-		UserID:      userID,
-		StartDate:   "08-2025",
-		EndDate:     &end,
+		ID:            id,
+		ServiceName:   "HBO",
+		Price:         700,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-08-01",
+		EndDate:       &end,
 	}
 	require.NoError(t, repo.Update(context.Background(), update))
 
@@ -117,16 +127,18 @@ func TestRepositoryDelete(t *testing.T) {
 	repo := repository.New(testPool)
 	userID := uuid.NewString()
 	sub := domain.Subscription{
-		ServiceName: "Netflix",
-		Price:       500,
-		UserID:      userID,
-		StartDate:   "07-2025",
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
 	}
 
 	id, err := repo.Create(context.Background(), sub)
 	require.NoError(t, err)
 
-	require.NoError(t, repo.Delete(context.Background(), id))
+	require.NoError(t, repo.Delete(context.Background(), id, 0))
 
 	_, err = repo.GetByID(context.Background(), id)
 	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
@@ -140,25 +152,161 @@ func TestRepositoryListFilters(t *testing.T) {
 	otherUser := uuid.NewString()
 
 	_, err := repo.Create(context.Background(), domain.Subscription{
-		ServiceName: "Netflix",
-		Price:       500,
-		UserID:      userID,
-		StartDate:   "07-2025",
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
 	})
 	require.NoError(t, err)
 
 	_, err = repo.Create(context.Background(), domain.Subscription{
-		ServiceName: "Spotify",
-		Price:       300,
-		UserID:      otherUser,
-		StartDate:   "07-2025",
+		ServiceName:   "Spotify",
+		Price:         300,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        otherUser,
+		StartDate:     "2025-07-01",
 	})
 	require.NoError(t, err)
 
-	items, err := repo.List(context.Background(), userID, "")
+	page, err := repo.List(context.Background(), domain.ListFilter{UserID: userID}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt, Desc: true}))
 	require.NoError(t, err)
-	require.Len(t, items, 1)
-	require.Equal(t, "Netflix", items[0].ServiceName)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "Netflix", page.Items[0].ServiceName)
+	require.Empty(t, page.NextCursor)
+}
+
+func TestRepositoryListRichFilters(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	end := "2025-09-30"
+	fixtures := []domain.Subscription{
+		{ServiceName: "Netflix Premium", Price: 900, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-01-01", EndDate: &end},
+		{ServiceName: "Spotify", Price: 300, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-06-01"},
+		{ServiceName: "YouTube 100%", Price: 200, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-11-01"},
+	}
+	for _, sub := range fixtures {
+		_, err := repo.Create(context.Background(), sub)
+		require.NoError(t, err)
+	}
+
+	minPrice, maxPrice := 250, 900
+	tests := []struct {
+		name   string
+		filter domain.ListFilter
+		want   []string
+	}{
+		{name: "search is case-insensitive", filter: domain.ListFilter{Search: "FLIX"}, want: []string{"Netflix Premium"}},
+		{name: "search escapes wildcards", filter: domain.ListFilter{Search: "100%"}, want: []string{"YouTube 100%"}},
+		{name: "price range", filter: domain.ListFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, want: []string{"Netflix Premium", "Spotify"}},
+		{name: "active in month", filter: domain.ListFilter{ActiveIn: "10-2025"}, want: []string{"Spotify"}},
+		{name: "start range", filter: domain.ListFilter{StartFrom: "05-2025", StartTo: "12-2025"}, want: []string{"Spotify", "YouTube 100%"}},
+		{name: "end range", filter: domain.ListFilter{EndFrom: "09-2025"}, want: []string{"Netflix Premium"}},
+		{name: "open ended", filter: domain.ListFilter{OpenEnded: true}, want: []string{"Spotify", "YouTube 100%"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.UserID = userID
+			page, err := repo.List(context.Background(), tt.filter, firstPage(10, domain.Sort{Field: domain.SortByServiceName}))
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(page.Items))
+			for _, item := range page.Items {
+				names = append(names, item.ServiceName)
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func firstPage(limit int, sort domain.Sort) domain.Pagination {
+	return domain.Pagination{Limit: limit, Sort: sort}
+}
+
+func nextPage(t *testing.T, prev domain.Pagination, cursor string) domain.Pagination {
+	t.Helper()
+	after, err := domain.DecodeCursor(cursor)
+	require.NoError(t, err)
+	prev.After = &after
+	return prev
+}
+
+func TestRepositoryListPagination(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	// Two rows share a price so the id tie-breaker is exercised.
+	prices := []int{300, 100, 200, 200, 500}
+	for i, price := range prices {
+		_, err := repo.Create(context.Background(), domain.Subscription{
+			ServiceName:   fmt.Sprintf("Service %d", i),
+			Price:         price,
+			Currency:      domain.DefaultCurrency,
+			BillingPeriod: domain.BillingMonthly,
+			UserID:        userID,
+			StartDate:     fmt.Sprintf("2025-%02d-01", i+1),
+		})
+		require.NoError(t, err)
+	}
+
+	sorts := []domain.Sort{
+		{Field: domain.SortByCreatedAt, Desc: true},
+		{Field: domain.SortByPrice},
+		{Field: domain.SortByPrice, Desc: true},
+		{Field: domain.SortByStartDate},
+		{Field: domain.SortByServiceName, Desc: true},
+	}
+
+	for _, sort := range sorts {
+		t.Run(sort.String(), func(t *testing.T) {
+			page := firstPage(2, sort)
+			seen := make(map[string]struct{})
+			var collected []domain.Subscription
+
+			for {
+				result, err := repo.List(context.Background(), domain.ListFilter{UserID: userID}, page)
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(result.Items), 2)
+
+				for _, item := range result.Items {
+					_, dup := seen[item.ID]
+					require.False(t, dup, "subscription %s returned twice", item.ID)
+					seen[item.ID] = struct{}{}
+				}
+				collected = append(collected, result.Items...)
+
+				if result.NextCursor == "" {
+					break
+				}
+				page = nextPage(t, page, result.NextCursor)
+			}
+
+			require.Len(t, collected, len(prices))
+			for i := 1; i < len(collected); i++ {
+				prev, cur := collected[i-1], collected[i]
+				switch sort.Field {
+				case domain.SortByPrice:
+					if sort.Desc {
+						require.GreaterOrEqual(t, prev.Price, cur.Price)
+					} else {
+						require.LessOrEqual(t, prev.Price, cur.Price)
+					}
+				case domain.SortByStartDate:
+					require.Less(t, prev.StartDate, cur.StartDate)
+				case domain.SortByServiceName:
+					require.Greater(t, prev.ServiceName, cur.ServiceName)
+				}
+			}
+		})
+	}
 }
 
 func TestRepositoryTotal(t *testing.T) {
@@ -167,31 +315,809 @@ func TestRepositoryTotal(t *testing.T) {
 	repo := repository.New(testPool)
 	userID := uuid.NewString()
 
-	end := "09-2025"
+	end := "2025-09-30"
 	_, err := repo.Create(context.Background(), domain.Subscription{
-		ServiceName: "Netflix",
-		Price:       100,
-		UserID:      userID,
-		StartDate:   "07-2025",
-		EndDate:     &end,
+		ServiceName:   "Netflix",
+		Price:         100,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+		EndDate:       &end,
 	})
 	require.NoError(t, err)
 
 	_, err = repo.Create(context.Background(), domain.Subscription{
-		ServiceName: "Spotify",
-		Price:       200,
-		UserID:      userID,
-		StartDate:   "08-2025",
+		ServiceName:   "Spotify",
+		Price:         200,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-08-01",
 	})
 	require.NoError(t, err)
 
-	to := "09-2025"
-	total, err := repo.Total(context.Background(), domain.Subscription{
-		UserID:    userID,
-		StartDate: "07-2025",
-		EndDate:   &to,
+	total, _, err := repo.Total(context.Background(), domain.TotalFilter{
+		UserID:   userID,
+		From:     "07-2025",
+		To:       "09-2025",
+		Currency: domain.DefaultCurrency,
 	})
 	require.NoError(t, err)
 
 	require.Equal(t, int64(700), total)
 }
+
+func TestRepositoryTotalBreakdown(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+	otherUser := uuid.NewString()
+
+	end := "2025-09-30"
+	fixtures := []domain.Subscription{
+		{ServiceName: "Netflix", Price: 100, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-07-01", EndDate: &end},
+		{ServiceName: "Spotify", Price: 200, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-08-01"},
+		{ServiceName: "Spotify", Price: 50, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: otherUser, StartDate: "2025-01-01"},
+	}
+	for _, sub := range fixtures {
+		_, err := repo.Create(context.Background(), sub)
+		require.NoError(t, err)
+	}
+
+	filter := domain.TotalFilter{From: "07-2025", To: "09-2025", Currency: domain.DefaultCurrency}
+
+	total, _, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(850), total)
+
+	byService, _, err := repo.TotalBreakdown(context.Background(), filter, domain.GroupByServiceName)
+	require.NoError(t, err)
+	require.Equal(t, []domain.TotalBucket{
+		{Key: "Netflix", Amount: 300, Count: 1},
+		{Key: "Spotify", Amount: 550, Count: 2},
+	}, byService)
+
+	byMonth, _, err := repo.TotalBreakdown(context.Background(), filter, domain.GroupByMonth)
+	require.NoError(t, err)
+	require.Equal(t, []domain.TotalBucket{
+		{Key: "07-2025", Amount: 150, Count: 2},
+		{Key: "08-2025", Amount: 350, Count: 3},
+		{Key: "09-2025", Amount: 350, Count: 3},
+	}, byMonth)
+
+	for _, groupBy := range []domain.TotalGroupBy{domain.GroupByServiceName, domain.GroupByUserID, domain.GroupByMonth} {
+		buckets, _, err := repo.TotalBreakdown(context.Background(), filter, groupBy)
+		require.NoError(t, err)
+
+		var sum int64
+		for _, b := range buckets {
+			sum += b.Amount
+		}
+		require.Equal(t, total, sum, "buckets grouped by %s must sum to the total", groupBy)
+	}
+}
+
+func TestRepositoryTimeseries(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	end := "2025-08-31"
+	netflixID, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         100,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+		EndDate:       &end,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Spotify",
+		Price:         200,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-08-01",
+	})
+	require.NoError(t, err)
+
+	// Netflix is paused for its whole last month: it is not active then but still ends then.
+	require.NoError(t, repo.Pause(context.Background(), netflixID, "2025-08-01"))
+
+	points, _, err := repo.Timeseries(context.Background(), domain.TotalFilter{
+		UserID:   userID,
+		From:     "06-2025",
+		To:       "10-2025",
+		Currency: domain.DefaultCurrency,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []domain.TimeseriesPoint{
+		{Month: "06-2025"},
+		{Month: "07-2025", Amount: 100, Active: 1, Started: 1},
+		{Month: "08-2025", Amount: 200, Active: 1, Started: 1, Ended: 1},
+		{Month: "09-2025", Amount: 200, Active: 1},
+		{Month: "10-2025", Amount: 200, Active: 1},
+	}, points)
+}
+
+func TestRepositoryTotalConvertsCurrency(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	fixtures := []domain.Subscription{
+		{ServiceName: "Netflix", Price: 10, Currency: "EUR", BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-07-01"},
+		{ServiceName: "Spotify", Price: 300, Currency: "RUB", BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-07-01"},
+	}
+	for _, sub := range fixtures {
+		_, err := repo.Create(context.Background(), sub)
+		require.NoError(t, err)
+	}
+
+	filter := domain.TotalFilter{UserID: userID, From: "07-2025", To: "08-2025", Currency: "RUB"}
+
+	_, _, err := repo.Total(context.Background(), filter)
+	require.ErrorIs(t, err, domain.ErrMissingExchangeRate)
+	require.ErrorContains(t, err, "EUR to RUB")
+	_, _, err = repo.TotalBreakdown(context.Background(), filter, domain.GroupByServiceName)
+	require.ErrorIs(t, err, domain.ErrMissingExchangeRate)
+	_, _, err = repo.Timeseries(context.Background(), filter)
+	require.ErrorIs(t, err, domain.ErrMissingExchangeRate)
+
+	// A breakdown by currency keeps every bucket in its own currency and needs no rate.
+	byCurrency, currency, err := repo.TotalBreakdown(context.Background(), filter, domain.GroupByCurrency)
+	require.NoError(t, err)
+	require.Empty(t, currency)
+	require.Equal(t, []domain.TotalBucket{
+		{Key: "EUR", Amount: 2 * 10, Count: 1},
+		{Key: "RUB", Amount: 2 * 300, Count: 1},
+	}, byCurrency)
+
+	_, err = testPool.Exec(context.Background(),
+		`INSERT INTO exchange_rates (base_currency, quote_currency, rate) VALUES ('EUR', 'RUB', 98.55)`)
+	require.NoError(t, err)
+
+	total, currency, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, "RUB", currency)
+	// 10 EUR rounds to 986 RUB per month.
+	require.Equal(t, int64(2*986+2*300), total)
+
+	// Subscriptions in several currencies are converted to the default one when none is requested.
+	filter.Currency = ""
+	total, currency, err = repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, domain.DefaultCurrency, currency)
+	require.Equal(t, int64(2*986+2*300), total)
+}
+
+func TestRepositoryTotalNativeCurrency(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	_, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix", Price: 15, Currency: "USD", BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "2025-07-01",
+	})
+	require.NoError(t, err)
+
+	// Without a requested currency a total over USD subscriptions stays in USD and needs no rate.
+	filter := domain.TotalFilter{UserID: userID, From: "07-2025", To: "08-2025"}
+	total, currency, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, "USD", currency)
+	require.Equal(t, int64(2*15), total)
+
+	byService, currency, err := repo.TotalBreakdown(context.Background(), filter, domain.GroupByServiceName)
+	require.NoError(t, err)
+	require.Equal(t, "USD", currency)
+	require.Equal(t, []domain.TotalBucket{{Key: "Netflix", Amount: 2 * 15, Count: 1}}, byService)
+
+	points, currency, err := repo.Timeseries(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, "USD", currency)
+	require.Len(t, points, 2)
+
+	// Asking for a single total in another currency still needs the rate.
+	filter.Currency = "RUB"
+	_, _, err = repo.Total(context.Background(), filter)
+	require.ErrorIs(t, err, domain.ErrMissingExchangeRate)
+	require.ErrorContains(t, err, "USD to RUB")
+}
+
+func TestRepositoryTotalBillingPeriods(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	fixtures := []domain.Subscription{
+		// Charged on 1, 8, 15, 22 and 29 July, then 5, 12, 19 and 26 August.
+		{ServiceName: "Gym", Price: 10, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingWeekly, UserID: userID, StartDate: "2025-07-01"},
+		// Charged in April and July only.
+		{ServiceName: "Magazine", Price: 100, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingQuarterly, UserID: userID, StartDate: "2025-04-01"},
+		// Charged in January 2025 and January 2026, both outside the window.
+		{ServiceName: "Cloud", Price: 1000, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingYearly, UserID: userID, StartDate: "2025-01-01"},
+	}
+	for _, sub := range fixtures {
+		_, err := repo.Create(context.Background(), sub)
+		require.NoError(t, err)
+	}
+
+	filter := domain.TotalFilter{UserID: userID, From: "07-2025", To: "08-2025", Currency: domain.DefaultCurrency}
+
+	total, _, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(9*10+100), total)
+
+	points, _, err := repo.Timeseries(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, []domain.TimeseriesPoint{
+		{Month: "07-2025", Amount: 5*10 + 100, Active: 3},
+		{Month: "08-2025", Amount: 4 * 10, Active: 3},
+	}, points)
+}
+
+func TestRepositoryTotalLongRunning(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	fixtures := []domain.Subscription{
+		// Charged on the last day of every month since 1990, so on 28 February 2025.
+		{ServiceName: "Phone", Price: 100, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingMonthly, UserID: userID, StartDate: "1990-01-31"},
+		// Charged every week since 3 January 2000, a Monday: on 3, 10, 17 and 24 February 2025.
+		{ServiceName: "Gym", Price: 10, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingWeekly, UserID: userID, StartDate: "2000-01-03"},
+		// Charged every November since 1995, so not in the period.
+		{ServiceName: "Domain", Price: 1000, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingYearly, UserID: userID, StartDate: "1995-11-15"},
+		// Charged in February, May, August and November since 2001.
+		{ServiceName: "Magazine", Price: 50, Currency: domain.DefaultCurrency, BillingPeriod: domain.BillingQuarterly, UserID: userID, StartDate: "2001-02-10"},
+	}
+	for _, sub := range fixtures {
+		_, err := repo.Create(context.Background(), sub)
+		require.NoError(t, err)
+	}
+
+	total, _, err := repo.Total(context.Background(), domain.TotalFilter{UserID: userID, From: "02-2025", To: "02-2025", Currency: domain.DefaultCurrency})
+	require.NoError(t, err)
+	require.Equal(t, int64(100+4*10+50), total)
+}
+
+func TestRepositoryTotalDailyProration(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	// Charged on 15 July, 15 August and 15 September; the last period is active for 6 of its 30 days.
+	end := "2025-09-20"
+	id, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         300,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-15",
+		EndDate:       &end,
+	})
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, "2025-07-15", got.StartDate)
+	require.NotNil(t, got.EndDate)
+	require.Equal(t, "2025-09-20", *got.EndDate)
+
+	filter := domain.TotalFilter{UserID: userID, From: "07-2025", To: "12-2025", Currency: domain.DefaultCurrency, Proration: domain.ProrationNone}
+
+	total, _, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(900), total)
+
+	filter.Proration = domain.ProrationDaily
+	total, _, err = repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(300+300+60), total)
+}
+
+func TestRepositoryPriceChanges(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	id, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         100,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AddPriceChange(context.Background(), domain.PriceChange{SubscriptionID: id, Price: 150, EffectiveFrom: "2025-09-01"}))
+	require.NoError(t, repo.AddPriceChange(context.Background(), domain.PriceChange{SubscriptionID: id, Price: 200, EffectiveFrom: "2025-10-15"}))
+
+	err = repo.AddPriceChange(context.Background(), domain.PriceChange{SubscriptionID: uuid.NewString(), Price: 150, EffectiveFrom: "2025-09-01"})
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+
+	changes, err := repo.ListPriceChanges(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, []domain.PriceChange{
+		{SubscriptionID: id, Price: 150, EffectiveFrom: "09-2025"},
+		{SubscriptionID: id, Price: 200, EffectiveFrom: "2025-10-15"},
+	}, changes)
+
+	// The October charge on the 1st predates the change on the 15th.
+	points, _, err := repo.Timeseries(context.Background(), domain.TotalFilter{UserID: userID, From: "07-2025", To: "11-2025", Currency: domain.DefaultCurrency})
+	require.NoError(t, err)
+	amounts := make([]int64, len(points))
+	for i, p := range points {
+		amounts[i] = p.Amount
+	}
+	require.Equal(t, []int64{100, 100, 150, 150, 200}, amounts)
+}
+
+func TestRepositoryPauses(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	id, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Gym",
+		Price:         100,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-05-01",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Pause(context.Background(), id, "2025-06-01"))
+	require.NoError(t, repo.Resume(context.Background(), id, "2025-09-01"))
+	require.ErrorIs(t, repo.Resume(context.Background(), id, "2025-10-01"), domain.ErrSubscriptionNotPaused)
+	require.ErrorIs(t, repo.Pause(context.Background(), uuid.NewString(), "2025-06-01"), domain.ErrSubscriptionNotFound)
+
+	got, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	resumed := "09-2025"
+	require.Equal(t, []domain.Pause{{PausedFrom: "06-2025", ResumedFrom: &resumed}}, got.Pauses)
+
+	filter := domain.TotalFilter{UserID: userID, From: "05-2025", To: "09-2025", Currency: domain.DefaultCurrency}
+	total, _, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), total)
+
+	points, _, err := repo.Timeseries(context.Background(), filter)
+	require.NoError(t, err)
+	actives := make([]int64, len(points))
+	for i, p := range points {
+		actives[i] = p.Active
+	}
+	require.Equal(t, []int64{1, 0, 0, 0, 1}, actives)
+
+	page, err := repo.List(context.Background(), domain.ListFilter{UserID: userID, ActiveIn: "07-2025"}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+
+	page, err = repo.List(context.Background(), domain.ListFilter{UserID: userID, ActiveIn: "09-2025"}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+}
+
+func TestRepositoryTrials(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	// Charged from September on; July and August are the trial.
+	trialEnd := "2025-08-31"
+	_, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         100,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+		TrialEnd:      &trialEnd,
+	})
+	require.NoError(t, err)
+
+	total, _, err := repo.Total(context.Background(), domain.TotalFilter{UserID: userID, From: "07-2025", To: "10-2025", Currency: domain.DefaultCurrency})
+	require.NoError(t, err)
+	require.Equal(t, int64(200), total)
+
+	today := time.Now()
+	endingSoon := today.AddDate(0, 0, 5).Format("2006-01-02")
+	id, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Spotify",
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     today.AddDate(0, 0, -10).Format("2006-01-02"),
+		TrialEnd:      &endingSoon,
+	})
+	require.NoError(t, err)
+
+	within := 7
+	page, err := repo.List(context.Background(), domain.ListFilter{UserID: userID, TrialEndsWithin: &within}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, id, page.Items[0].ID)
+	require.Equal(t, 0, page.Items[0].Price)
+
+	within = 3
+	page, err = repo.List(context.Background(), domain.ListFilter{UserID: userID, TrialEndsWithin: &within}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+}
+
+func TestRepositoryStatus(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+	today := time.Now()
+
+	create := func(name string, start time.Time, end *string) string {
+		id, err := repo.Create(context.Background(), domain.Subscription{
+			ServiceName:   name,
+			Price:         100,
+			Currency:      domain.DefaultCurrency,
+			BillingPeriod: domain.BillingMonthly,
+			UserID:        userID,
+			StartDate:     start.Format("2006-01-02"),
+			EndDate:       end,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	activeID := create("Active", today.AddDate(0, -2, 0), nil)
+	expiredID := create("Expired", today.AddDate(0, -2, 0), &yesterday)
+	pausedID := create("Paused", today.AddDate(0, -2, 0), nil)
+	cancelledID := create("Cancelled", today.AddDate(0, -2, 0), nil)
+	pendingID := create("Pending", today.AddDate(0, -2, 0), nil)
+
+	require.NoError(t, repo.Pause(context.Background(), pausedID, today.AddDate(0, 0, -3).Format("2006-01-02")))
+	require.NoError(t, repo.Cancel(context.Background(), cancelledID, yesterday, "too expensive"))
+
+	// A cancellation at the end of next month is still billed until then, so it stays active.
+	cancelEnd := today.AddDate(0, 1, 0).Format("2006-01-02")
+	require.NoError(t, repo.Cancel(context.Background(), pendingID, cancelEnd, "moving"))
+
+	want := map[domain.SubscriptionStatus][]string{
+		domain.StatusActive:    {activeID, pendingID},
+		domain.StatusExpired:   {expiredID},
+		domain.StatusPaused:    {pausedID},
+		domain.StatusCancelled: {cancelledID},
+	}
+	for status, ids := range want {
+		for _, id := range ids {
+			got, err := repo.GetByID(context.Background(), id)
+			require.NoError(t, err)
+			require.Equal(t, status, got.Status, id)
+		}
+
+		page, err := repo.List(context.Background(), domain.ListFilter{UserID: userID, Status: status}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+		require.NoError(t, err)
+		got := make([]string, len(page.Items))
+		for i, item := range page.Items {
+			got[i] = item.ID
+		}
+		require.ElementsMatch(t, ids, got)
+	}
+
+	// The pending cancellation is charged this month, matching its status.
+	month := today.Format("01-2006")
+	total, _, err := repo.Total(context.Background(), domain.TotalFilter{UserID: userID, ServiceName: "Pending", From: month, To: month, Currency: domain.DefaultCurrency})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), total)
+
+	cancelled, err := repo.GetByID(context.Background(), cancelledID)
+	require.NoError(t, err)
+	require.NotNil(t, cancelled.CancelledAt)
+	require.Equal(t, "too expensive", cancelled.CancelReason)
+	require.NotNil(t, cancelled.EndDate)
+}
+
+func TestRepositoryTrash(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	id, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(context.Background(), id, 0))
+	require.ErrorIs(t, repo.Delete(context.Background(), id, 0), domain.ErrSubscriptionNotFound)
+
+	page, err := repo.List(context.Background(), domain.ListFilter{UserID: userID}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+
+	filter := domain.TotalFilter{UserID: userID, From: "07-2025", To: "08-2025", Currency: domain.DefaultCurrency}
+	total, _, err := repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Zero(t, total)
+
+	trash, err := repo.List(context.Background(), domain.ListFilter{UserID: userID, Deleted: true}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Len(t, trash.Items, 1)
+	require.NotNil(t, trash.Items[0].DeletedAt)
+
+	require.NoError(t, repo.Restore(context.Background(), id))
+	require.ErrorIs(t, repo.Restore(context.Background(), id), domain.ErrSubscriptionNotFound)
+
+	total, _, err = repo.Total(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), total)
+
+	// Only trash older than the cutoff is purged.
+	require.NoError(t, repo.Delete(context.Background(), id, 0))
+	purged, err := repo.Purge(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged)
+
+	purged, err = repo.Purge(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+	require.ErrorIs(t, repo.Restore(context.Background(), id), domain.ErrSubscriptionNotFound)
+}
+
+func TestRepositoryEvents(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	ctx := domain.WithRequestID(domain.WithActor(context.Background(), "alice"), "req-1")
+
+	sub := domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        uuid.NewString(),
+		StartDate:     "2025-07-01",
+	}
+	id, err := repo.Create(ctx, sub)
+	require.NoError(t, err)
+
+	sub.ID = id
+	sub.Price = 600
+	require.NoError(t, repo.Update(ctx, sub))
+	require.NoError(t, repo.Pause(context.Background(), id, "2025-09-01"))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+
+	// A failed change leaves no trace.
+	require.ErrorIs(t, repo.Update(ctx, sub), domain.ErrSubscriptionNotFound)
+
+	_, err = repo.Purge(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	events, err := repo.ListEvents(context.Background(), id)
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+
+	actions := make([]domain.EventAction, len(events))
+	for i, event := range events {
+		actions[i] = event.Action
+		require.Equal(t, id, event.SubscriptionID)
+	}
+	require.Equal(t, []domain.EventAction{
+		domain.EventCreate, domain.EventUpdate, domain.EventPause, domain.EventDelete, domain.EventPurge,
+	}, actions)
+
+	require.Nil(t, events[0].Before)
+	require.Equal(t, "alice", events[0].Actor)
+	require.Equal(t, "req-1", events[0].RequestID)
+	require.JSONEq(t, `500`, string(jsonField(t, events[1].Before, "price")))
+	require.JSONEq(t, `600`, string(jsonField(t, events[1].After, "price")))
+	require.Equal(t, domain.SystemActor, events[2].Actor)
+	var pauses []map[string]any
+	require.NoError(t, json.Unmarshal(jsonField(t, events[2].After, "pauses"), &pauses))
+	require.Len(t, pauses, 1)
+	require.Nil(t, events[4].After)
+	require.NotNil(t, events[4].Before)
+
+	_, err = testPool.Exec(context.Background(), "DELETE FROM subscription_events WHERE subscription_id = $1", id)
+	require.Error(t, err)
+}
+
+func jsonField(t *testing.T, raw json.RawMessage, field string) json.RawMessage {
+	t.Helper()
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &fields))
+	return fields[field]
+}
+
+func TestRepositoryVersion(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	sub := domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        uuid.NewString(),
+		StartDate:     "2025-07-01",
+	}
+	id, err := repo.Create(context.Background(), sub)
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.Version)
+
+	sub.ID = id
+	sub.Version = 1
+	sub.Price = 600
+	require.NoError(t, repo.Update(context.Background(), sub))
+
+	// Every change moves the version on, including ones outside the subscriptions row.
+	require.NoError(t, repo.Pause(context.Background(), id, "2025-09-01"))
+	got, err = repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got.Version)
+
+	require.ErrorIs(t, repo.Update(context.Background(), sub), domain.ErrPreconditionFailed)
+	require.ErrorIs(t, repo.Delete(context.Background(), id, 2), domain.ErrPreconditionFailed)
+	require.NoError(t, repo.Delete(context.Background(), id, 3))
+}
+
+func TestRepositoryInTx(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+	sub := domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "2025-07-01",
+	}
+
+	kept, err := repo.Create(context.Background(), sub)
+	require.NoError(t, err)
+
+	var created string
+	rollback := errors.New("rollback")
+	err = repo.InTx(context.Background(), func(ctx context.Context) error {
+		created, err = repo.Create(ctx, sub)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, kept, 0))
+
+		// A failed change inside the transaction does not abort it.
+		require.ErrorIs(t, repo.Delete(ctx, kept, 0), domain.ErrSubscriptionNotFound)
+		_, err := repo.GetByID(ctx, created)
+		require.NoError(t, err)
+		return rollback
+	})
+	require.ErrorIs(t, err, rollback)
+
+	_, err = repo.GetByID(context.Background(), created)
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+	_, err = repo.GetByID(context.Background(), kept)
+	require.NoError(t, err)
+
+	events, err := repo.ListEvents(context.Background(), created)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestRepositoryExport(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	userID := uuid.NewString()
+
+	var ids []string
+	for _, name := range []string{"Netflix", "Spotify", "Yandex Plus"} {
+		id, err := repo.Create(context.Background(), domain.Subscription{
+			ServiceName:   name,
+			Price:         300,
+			Currency:      domain.DefaultCurrency,
+			BillingPeriod: domain.BillingMonthly,
+			UserID:        userID,
+			StartDate:     "2025-07-01",
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	_, err := repo.Create(context.Background(), domain.Subscription{
+		ServiceName:   "Netflix",
+		Price:         300,
+		Currency:      domain.DefaultCurrency,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        uuid.NewString(),
+		StartDate:     "2025-07-01",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(context.Background(), ids[1], 0))
+
+	var got []string
+	for sub, err := range repo.Export(context.Background(), domain.ListFilter{UserID: userID}) {
+		require.NoError(t, err)
+		got = append(got, sub.ID)
+	}
+	require.Equal(t, []string{ids[0], ids[2]}, got)
+}
+
+func TestRepositoryOwner(t *testing.T) {
+	cleanupDB(t)
+
+	repo := repository.New(testPool)
+	owner, other := uuid.NewString(), uuid.NewString()
+
+	var ids []string
+	for _, userID := range []string{owner, other} {
+		id, err := repo.Create(context.Background(), domain.Subscription{
+			ServiceName:   "Netflix",
+			Price:         500,
+			Currency:      domain.DefaultCurrency,
+			BillingPeriod: domain.BillingMonthly,
+			UserID:        userID,
+			StartDate:     "2025-07-01",
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	page, err := repo.List(context.Background(), domain.ListFilter{OwnerID: owner}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, ids[0], page.Items[0].ID)
+
+	// The owner restriction applies on top of a user_id filter for another user.
+	page, err = repo.List(context.Background(), domain.ListFilter{UserID: other, OwnerID: owner}, firstPage(10, domain.Sort{Field: domain.SortByCreatedAt}))
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+
+	total, _, err := repo.Total(context.Background(), domain.TotalFilter{OwnerID: owner, From: "07-2025", To: "07-2025", Currency: domain.DefaultCurrency})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), total)
+
+	got, err := repo.Owner(context.Background(), ids[0])
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+
+	// The owner stays known in the trash and after the subscription is purged.
+	require.NoError(t, repo.Delete(context.Background(), ids[0], 0))
+	got, err = repo.Owner(context.Background(), ids[0])
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+
+	_, err = repo.Purge(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	got, err = repo.Owner(context.Background(), ids[0])
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+
+	_, err = repo.Owner(context.Background(), uuid.NewString())
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+}
@@ -0,0 +1,31 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+// Cancel records a cancellation and ends the subscription on endDate. A trial running past the
+// new end date is cut short with it.
+func (r *Repository) Cancel(ctx context.Context, id string, endDate string, reason string) error {
+	query := `
+		UPDATE subscriptions
+		SET
+			end_date = to_date($2, 'YYYY-MM-DD'),
+			trial_end = LEAST(trial_end, to_date($2, 'YYYY-MM-DD')),
+			cancelled_at = NOW(),
+			cancel_reason = NULLIF($3, '')
+		WHERE id = $1
+	`
+
+	return r.audited(ctx, id, domain.EventCancel, false, 0, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, id, endDate, reason); err != nil {
+			return fmt.Errorf("cancel subscription: %w", err)
+		}
+		return nil
+	})
+}
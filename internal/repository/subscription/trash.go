@@ -0,0 +1,52 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"subscription_service/internal/domain"
+)
+
+// Restore takes a subscription out of the trash.
+func (r *Repository) Restore(ctx context.Context, id string) error {
+	return r.audited(ctx, id, domain.EventRestore, true, 0, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE subscriptions SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("restore subscription: %w", err)
+		}
+		return nil
+	})
+}
+
+// Purge permanently removes subscriptions deleted before the given time and returns how many were removed.
+// Their last state is kept in the audit trail.
+func (r *Repository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `
+		WITH doomed AS (
+			SELECT s.id, ` + subscriptionSnapshot + ` AS snapshot
+			FROM subscriptions s
+			WHERE s.deleted_at < $1
+			FOR UPDATE
+		),
+		purged AS (
+			DELETE FROM subscriptions
+			WHERE id IN (SELECT id FROM doomed)
+			RETURNING id
+		)
+		INSERT INTO subscription_events (subscription_id, action, before, after, actor, request_id)
+		SELECT d.id, $2, d.snapshot, NULL, $3, $4
+		FROM doomed d
+		JOIN purged p ON p.id = d.id
+	`
+
+	result, err := r.conn(ctx).Exec(
+		ctx, query, deletedBefore, domain.EventPurge, domain.ActorFromContext(ctx), domain.RequestIDFromContext(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("purge subscriptions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,15 @@
+package apikey
+
+import (
+	"context"
+
+	"subscription_service/internal/domain"
+)
+
+//go:generate mockgen -source=contract.go -destination=mock_test.go -package=apikey_test
+type repository interface {
+	Create(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, keyHash string) (domain.APIKey, error)
+}
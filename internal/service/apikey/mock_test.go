@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contract.go -destination=mock_test.go -package=apikey_test
+//
+
+// Package apikey_test is a generated GoMock package.
+package apikey_test
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "subscription_service/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *Mockrepository) Authenticate(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, keyHash)
+	ret0, _ := ret[0].(domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockrepositoryMockRecorder) Authenticate(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*Mockrepository)(nil).Authenticate), ctx, keyHash)
+}
+
+// Create mocks base method.
+func (m *Mockrepository) Create(ctx context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key, keyHash)
+	ret0, _ := ret[0].(domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockrepositoryMockRecorder) Create(ctx, key, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*Mockrepository)(nil).Create), ctx, key, keyHash)
+}
+
+// List mocks base method.
+func (m *Mockrepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockrepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mockrepository)(nil).List), ctx)
+}
+
+// Revoke mocks base method.
+func (m *Mockrepository) Revoke(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockrepositoryMockRecorder) Revoke(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*Mockrepository)(nil).Revoke), ctx, id)
+}
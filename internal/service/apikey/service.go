@@ -0,0 +1,75 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"subscription_service/internal/domain"
+)
+
+// secretBytes is the amount of randomness in a secret. Secrets this long cannot be guessed, so a
+// fast hash is enough to store them.
+const secretBytes = 32
+
+type Service struct {
+	repo repository
+}
+
+func New(repo repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create issues a new key and returns it with its secret. The secret is not stored and cannot be
+// retrieved again.
+func (s *Service) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, string, error) {
+	validated, err := validateKey(key, time.Now())
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	random := make([]byte, secretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("generate api key: %w", err)
+	}
+	secret := domain.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	created, err := s.repo.Create(ctx, validated, hashSecret(secret))
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	return created, secret, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *Service) Revoke(ctx context.Context, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	return s.repo.Revoke(ctx, id)
+}
+
+// Authenticate returns the live key with the given secret. Unknown, revoked and expired keys all
+// fail with ErrInvalidAPIKey.
+func (s *Service) Authenticate(ctx context.Context, secret string) (domain.APIKey, error) {
+	if !strings.HasPrefix(secret, domain.APIKeyPrefix) {
+		return domain.APIKey{}, domain.ErrInvalidAPIKey
+	}
+
+	return s.repo.Authenticate(ctx, hashSecret(secret))
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
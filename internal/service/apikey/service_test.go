@@ -0,0 +1,108 @@
+package apikey_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	apiKeyService "subscription_service/internal/service/apikey"
+)
+
+func TestServiceCreate_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := apiKeyService.New(repo)
+
+	expired := time.Now().Add(-time.Hour)
+	_, _, err := svc.Create(context.Background(), domain.APIKey{
+		Name:      "  ",
+		UserID:    "not-a-uuid",
+		Scopes:    []string{"admin", "Admin Scope"},
+		ExpiresAt: &expired,
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+
+	var fields []string
+	for _, field := range vErr.Fields() {
+		fields = append(fields, field.Field+"="+field.Code)
+	}
+	require.Equal(t, []string{
+		"name=invalid_api_key_name",
+		"user_id=invalid_user_id",
+		"scopes=invalid_scope",
+		"expires_at=invalid_expiry",
+	}, fields)
+}
+
+func TestServiceCreate_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := apiKeyService.New(repo)
+
+	var storedHash string
+	repo.EXPECT().Create(gomock.Any(), domain.APIKey{Name: "billing", Scopes: []string{"admin"}}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, key domain.APIKey, keyHash string) (domain.APIKey, error) {
+			storedHash = keyHash
+			key.ID = uuid.NewString()
+			return key, nil
+		})
+
+	key, secret, err := svc.Create(context.Background(), domain.APIKey{Name: " billing ", Scopes: []string{"admin", "admin"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, key.ID)
+	require.True(t, strings.HasPrefix(secret, domain.APIKeyPrefix))
+	require.NotContains(t, storedHash, secret)
+
+	// The secret is looked up by the hash stored at creation.
+	repo.EXPECT().Authenticate(gomock.Any(), storedHash).Return(key, nil)
+	got, err := svc.Authenticate(context.Background(), secret)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+}
+
+func TestServiceAuthenticate_NotAKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := apiKeyService.New(repo)
+
+	_, err := svc.Authenticate(context.Background(), "eyJhbGciOiJIUzI1NiJ9")
+	require.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+}
+
+func TestServiceRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := apiKeyService.New(repo)
+
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, svc.Revoke(context.Background(), "not-a-uuid"), &vErr)
+
+	id := uuid.NewString()
+	repo.EXPECT().Revoke(gomock.Any(), id).Return(domain.ErrAPIKeyNotFound)
+	require.ErrorIs(t, svc.Revoke(context.Background(), id), domain.ErrAPIKeyNotFound)
+}
+
+func TestServiceCreate_UserRequiredWithoutAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := apiKeyService.New(repo)
+
+	_, _, err := svc.Create(context.Background(), domain.APIKey{Name: "reports", Scopes: []string{"reports:read"}})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrMissingUserID)
+	require.Equal(t, "user_id", vErr.Fields()[0].Field)
+
+	userID := uuid.NewString()
+	repo.EXPECT().Create(gomock.Any(), domain.APIKey{Name: "reports", UserID: userID, Scopes: []string{"reports:read"}}, gomock.Any()).
+		Return(domain.APIKey{ID: uuid.NewString()}, nil)
+	_, _, err = svc.Create(context.Background(), domain.APIKey{Name: "reports", UserID: userID, Scopes: []string{"reports:read"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"subscription_service/internal/domain"
+)
+
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return &domain.ValidationError{Err: domain.NewFieldError("id", domain.ErrInvalidID)}
+	}
+	return nil
+}
+
+// validateKey checks a key to be created. Its scopes are the permissions it grants; duplicates are
+// dropped. Only admin keys may be created without a user ID.
+func validateKey(key domain.APIKey, now time.Time) (domain.APIKey, error) {
+	var errs domain.FieldErrors
+
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" || len([]rune(key.Name)) > domain.MaxAPIKeyNameLength {
+		errs.Add("name", domain.ErrInvalidAPIKeyName)
+	}
+
+	if key.UserID != "" {
+		if _, err := uuid.Parse(key.UserID); err != nil || strings.TrimSpace(key.UserID) != key.UserID {
+			errs.Add("user_id", domain.ErrInvalidUserID)
+		}
+	}
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if !domain.IsPermission(scope) {
+			errs.Add("scopes", domain.ErrInvalidScope)
+			break
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	key.Scopes = scopes
+
+	// A key without a user only reaches subscriptions through the admin permission.
+	if key.UserID == "" && !slices.Contains(key.Scopes, string(domain.PermAdmin)) {
+		errs.Add("user_id", domain.ErrMissingUserID)
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		errs.Add("expires_at", domain.ErrInvalidExpiry)
+	}
+
+	if err := errs.Err(); err != nil {
+		return domain.APIKey{}, err
+	}
+	return key, nil
+}
@@ -0,0 +1,14 @@
+package exchangerate
+
+import (
+	"context"
+
+	"subscription_service/internal/domain"
+)
+
+//go:generate mockgen -source=contract.go -destination=mock_test.go -package=exchangerate_test
+type repository interface {
+	Upsert(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error)
+	List(ctx context.Context) ([]domain.ExchangeRate, error)
+	Delete(ctx context.Context, base string, quote string) error
+}
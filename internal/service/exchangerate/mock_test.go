@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contract.go -destination=mock_test.go -package=exchangerate_test
+//
+
+// Package exchangerate_test is a generated GoMock package.
+package exchangerate_test
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "subscription_service/internal/domain"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *Mockrepository) Delete(ctx context.Context, base, quote string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, base, quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockrepositoryMockRecorder) Delete(ctx, base, quote any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockrepository)(nil).Delete), ctx, base, quote)
+}
+
+// List mocks base method.
+func (m *Mockrepository) List(ctx context.Context) ([]domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockrepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mockrepository)(nil).List), ctx)
+}
+
+// Upsert mocks base method.
+func (m *Mockrepository) Upsert(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, rate)
+	ret0, _ := ret[0].(domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockrepositoryMockRecorder) Upsert(ctx, rate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*Mockrepository)(nil).Upsert), ctx, rate)
+}
@@ -0,0 +1,36 @@
+package exchangerate
+
+import (
+	"context"
+
+	"subscription_service/internal/domain"
+)
+
+type Service struct {
+	repo repository
+}
+
+func New(repo repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) Set(ctx context.Context, rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	validated, err := validateRate(rate)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+
+	return s.repo.Upsert(ctx, validated)
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.ExchangeRate, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *Service) Delete(ctx context.Context, base string, quote string) error {
+	if err := validateCurrencyPair(base, quote); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, base, quote)
+}
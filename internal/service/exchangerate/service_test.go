@@ -0,0 +1,51 @@
+package exchangerate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	exchangeRateService "subscription_service/internal/service/exchangerate"
+)
+
+func TestServiceSet_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := exchangeRateService.New(repo)
+
+	tests := []struct {
+		name string
+		rate domain.ExchangeRate
+		want error
+	}{
+		{name: "lowercase currency", rate: domain.ExchangeRate{Base: "eur", Quote: "RUB", Rate: "1"}, want: domain.ErrInvalidCurrency},
+		{name: "same currency", rate: domain.ExchangeRate{Base: "EUR", Quote: "EUR", Rate: "1"}, want: domain.ErrInvalidCurrency},
+		{name: "zero rate", rate: domain.ExchangeRate{Base: "EUR", Quote: "RUB", Rate: "0.0"}, want: domain.ErrInvalidExchangeRate},
+		{name: "not a decimal", rate: domain.ExchangeRate{Base: "EUR", Quote: "RUB", Rate: "1e3"}, want: domain.ErrInvalidExchangeRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Set(context.Background(), tt.rate)
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, tt.want)
+		})
+	}
+}
+
+func TestServiceSet_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := exchangeRateService.New(repo)
+
+	rate := domain.ExchangeRate{Base: "USD", Quote: "RUB", Rate: "91.25"}
+	repo.EXPECT().Upsert(gomock.Any(), rate).Return(rate, nil)
+
+	got, err := svc.Set(context.Background(), rate)
+	require.NoError(t, err)
+	require.Equal(t, rate, got)
+}
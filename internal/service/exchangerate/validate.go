@@ -0,0 +1,42 @@
+package exchangerate
+
+import (
+	"regexp"
+	"strconv"
+
+	"subscription_service/internal/domain"
+)
+
+// ratePattern matches the exchange_rates.rate column, NUMERIC(20, 10).
+var ratePattern = regexp.MustCompile(`^\d{1,10}(\.\d{1,10})?$`)
+
+func validateCurrencyPair(base string, quote string) error {
+	var errs domain.FieldErrors
+	checkCurrencyPair(&errs, base, quote)
+	return errs.Err()
+}
+
+func checkCurrencyPair(errs *domain.FieldErrors, base string, quote string) {
+	if !domain.IsCurrencyCode(base) {
+		errs.Add("base", domain.ErrInvalidCurrency)
+	}
+	if !domain.IsCurrencyCode(quote) || quote == base {
+		errs.Add("quote", domain.ErrInvalidCurrency)
+	}
+}
+
+func validateRate(rate domain.ExchangeRate) (domain.ExchangeRate, error) {
+	var errs domain.FieldErrors
+	checkCurrencyPair(&errs, rate.Base, rate.Quote)
+
+	if !ratePattern.MatchString(rate.Rate) {
+		errs.Add("rate", domain.ErrInvalidExchangeRate)
+	} else if value, err := strconv.ParseFloat(rate.Rate, 64); err != nil || value <= 0 {
+		errs.Add("rate", domain.ErrInvalidExchangeRate)
+	}
+
+	if err := errs.Err(); err != nil {
+		return domain.ExchangeRate{}, err
+	}
+	return rate, nil
+}
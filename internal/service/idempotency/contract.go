@@ -0,0 +1,16 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"subscription_service/internal/domain"
+)
+
+//go:generate mockgen -source=contract.go -destination=mock_test.go -package=idempotency_test
+type repository interface {
+	Reserve(ctx context.Context, actor string, key string, requestHash string, expiresAt time.Time) (domain.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, actor string, key string, resp domain.StoredResponse) error
+	Release(ctx context.Context, actor string, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
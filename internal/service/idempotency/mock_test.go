@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contract.go -destination=mock_test.go -package=idempotency_test
+//
+
+// Package idempotency_test is a generated GoMock package.
+package idempotency_test
+
+import (
+	context "context"
+	reflect "reflect"
+	domain "subscription_service/internal/domain"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// Mockrepository is a mock of repository interface.
+type Mockrepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockrepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockrepositoryMockRecorder is the mock recorder for Mockrepository.
+type MockrepositoryMockRecorder struct {
+	mock *Mockrepository
+}
+
+// NewMockrepository creates a new mock instance.
+func NewMockrepository(ctrl *gomock.Controller) *Mockrepository {
+	mock := &Mockrepository{ctrl: ctrl}
+	mock.recorder = &MockrepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *Mockrepository) Complete(ctx context.Context, actor, key string, resp domain.StoredResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, actor, key, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockrepositoryMockRecorder) Complete(ctx, actor, key, resp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*Mockrepository)(nil).Complete), ctx, actor, key, resp)
+}
+
+// PurgeExpired mocks base method.
+func (m *Mockrepository) PurgeExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockrepositoryMockRecorder) PurgeExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*Mockrepository)(nil).PurgeExpired), ctx)
+}
+
+// Release mocks base method.
+func (m *Mockrepository) Release(ctx context.Context, actor, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, actor, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockrepositoryMockRecorder) Release(ctx, actor, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*Mockrepository)(nil).Release), ctx, actor, key)
+}
+
+// Reserve mocks base method.
+func (m *Mockrepository) Reserve(ctx context.Context, actor, key, requestHash string, expiresAt time.Time) (domain.IdempotencyKey, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, actor, key, requestHash, expiresAt)
+	ret0, _ := ret[0].(domain.IdempotencyKey)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockrepositoryMockRecorder) Reserve(ctx, actor, key, requestHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*Mockrepository)(nil).Reserve), ctx, actor, key, requestHash, expiresAt)
+}
@@ -0,0 +1,59 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"subscription_service/internal/domain"
+)
+
+type Service struct {
+	repo repository
+	ttl  time.Duration
+}
+
+// New returns a service that keeps responses for replay for ttl after the first request.
+func New(repo repository, ttl time.Duration) *Service {
+	return &Service{repo: repo, ttl: ttl}
+}
+
+// Begin claims key of actor for a request identified by requestHash. It returns nil when the
+// request should be handled, and the stored response when it is a retry of a request that already
+// completed. Keys of different actors never collide.
+func (s *Service) Begin(ctx context.Context, actor string, key string, requestHash string) (*domain.StoredResponse, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	record, reserved, err := s.repo.Reserve(ctx, actor, key, requestHash, time.Now().Add(s.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if record.Response == nil {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+
+	return record.Response, nil
+}
+
+// Complete stores the response to replay for key of actor.
+func (s *Service) Complete(ctx context.Context, actor string, key string, resp domain.StoredResponse) error {
+	return s.repo.Complete(ctx, actor, key, resp)
+}
+
+// Release gives up key after a request failed, so that a retry is handled again.
+func (s *Service) Release(ctx context.Context, actor string, key string) error {
+	return s.repo.Release(ctx, actor, key)
+}
+
+// PurgeExpired removes keys older than the TTL.
+func (s *Service) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.PurgeExpired(ctx)
+}
@@ -0,0 +1,71 @@
+package idempotency_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/domain"
+	idempotencyService "subscription_service/internal/service/idempotency"
+)
+
+func TestServiceBegin_Reserved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := idempotencyService.New(repo, time.Hour)
+
+	repo.EXPECT().Reserve(gomock.Any(), "alice", "key-1", "hash", gomock.Any()).
+		DoAndReturn(func(_ context.Context, actor string, key string, hash string, expiresAt time.Time) (domain.IdempotencyKey, bool, error) {
+			require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+			return domain.IdempotencyKey{Actor: actor, Key: key, RequestHash: hash, ExpiresAt: expiresAt}, true, nil
+		})
+
+	resp, err := svc.Begin(context.Background(), "alice", "key-1", "hash")
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestServiceBegin_Existing(t *testing.T) {
+	stored := &domain.StoredResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":"1"}`)}
+	tests := []struct {
+		name     string
+		existing domain.IdempotencyKey
+		want     *domain.StoredResponse
+		wantErr  error
+	}{
+		{name: "replay", existing: domain.IdempotencyKey{RequestHash: "hash", Response: stored}, want: stored},
+		{name: "different body", existing: domain.IdempotencyKey{RequestHash: "other", Response: stored}, wantErr: domain.ErrIdempotencyKeyReused},
+		{name: "in progress", existing: domain.IdempotencyKey{RequestHash: "hash"}, wantErr: domain.ErrIdempotencyKeyInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := idempotencyService.New(repo, time.Hour)
+
+			repo.EXPECT().Reserve(gomock.Any(), "alice", "key-1", "hash", gomock.Any()).Return(tt.existing, false, nil)
+
+			resp, err := svc.Begin(context.Background(), "alice", "key-1", "hash")
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestServiceBegin_InvalidKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := idempotencyService.New(NewMockrepository(ctrl), time.Hour)
+
+	for _, key := range []string{"", strings.Repeat("k", domain.MaxIdempotencyKeyLength+1), "key\n1"} {
+		_, err := svc.Begin(context.Background(), "alice", key, "hash")
+		var vErr *domain.ValidationError
+		require.ErrorAs(t, err, &vErr)
+		require.ErrorIs(t, vErr, domain.ErrInvalidIdempotencyKey)
+		require.Equal(t, "Idempotency-Key", vErr.Fields()[0].Field)
+	}
+}
@@ -0,0 +1,21 @@
+package idempotency
+
+import "subscription_service/internal/domain"
+
+// keyField names the header the key arrives in, so a rejected key points the client at it.
+const keyField = "Idempotency-Key"
+
+// validateKey accepts up to MaxIdempotencyKeyLength printable ASCII characters.
+func validateKey(key string) error {
+	if key == "" || len(key) > domain.MaxIdempotencyKeyLength {
+		return &domain.ValidationError{Err: domain.NewFieldError(keyField, domain.ErrInvalidIdempotencyKey)}
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return &domain.ValidationError{Err: domain.NewFieldError(keyField, domain.ErrInvalidIdempotencyKey)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"subscription_service/internal/domain"
+)
+
+// errBatchFailed rolls back an atomic batch; the failure itself is reported in the results.
+var errBatchFailed = errors.New("batch operation failed")
+
+// Batch applies operations in order, each validated like the single-item method it stands for.
+// In atomic mode the first failure undoes the operations before it and skips the rest.
+func (s *Service) Batch(ctx context.Context, mode domain.BatchMode, ops []domain.BatchOperation) ([]domain.BatchResult, error) {
+	mode, err := validateBatch(mode, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BatchResult, len(ops))
+	if mode == domain.BatchBestEffort {
+		for i, op := range ops {
+			results[i] = s.applyBatchOperation(ctx, op)
+		}
+		return results, nil
+	}
+
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		for i, op := range ops {
+			results[i] = s.applyBatchOperation(ctx, op)
+			if results[i].Err == nil {
+				continue
+			}
+
+			for j := range i {
+				results[j] = domain.BatchResult{Action: ops[j].Action, ID: ops[j].ID, Status: domain.BatchStatusRolledBack}
+			}
+			for j := i + 1; j < len(ops); j++ {
+				results[j] = domain.BatchResult{Action: ops[j].Action, ID: ops[j].ID, Status: domain.BatchStatusSkipped}
+			}
+			return errBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchFailed) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *Service) applyBatchOperation(ctx context.Context, op domain.BatchOperation) domain.BatchResult {
+	result := domain.BatchResult{Action: op.Action, ID: op.ID}
+
+	switch op.Action {
+	case domain.BatchCreate:
+		result.ID, result.Err = s.Create(ctx, op.Subscription)
+	case domain.BatchUpdate:
+		sub := op.Subscription
+		sub.ID = op.ID
+		sub.Version = op.Version
+		result.Err = s.Update(ctx, sub)
+	case domain.BatchDelete:
+		result.Err = s.Delete(ctx, op.ID, op.Version)
+	default:
+		result.Err = &domain.ValidationError{Err: domain.NewFieldError("op", domain.ErrInvalidBatchAction)}
+	}
+
+	result.Status = domain.BatchStatusOK
+	if result.Err != nil {
+		result.Status = domain.BatchStatusFailed
+	}
+	return result
+}
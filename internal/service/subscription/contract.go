@@ -2,16 +2,31 @@ package subscription
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"subscription_service/internal/domain"
 )
 
 //go:generate mockgen -source=contract.go -destination=mock_test.go -package=subscription_test
 type repository interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
 	Create(ctx context.Context, sub domain.Subscription) (string, error)
 	GetByID(ctx context.Context, id string) (domain.Subscription, error)
-	List(ctx context.Context, userID string, serviceName string) ([]domain.Subscription, error)
+	Owner(ctx context.Context, id string) (string, error)
+	List(ctx context.Context, filter domain.ListFilter, page domain.Pagination) (domain.SubscriptionPage, error)
+	Export(ctx context.Context, filter domain.ListFilter) iter.Seq2[domain.Subscription, error]
 	Update(ctx context.Context, sub domain.Subscription) error
-	Delete(ctx context.Context, id string) error
-	Total(ctx context.Context, filter domain.Subscription) (int64, error)
+	Delete(ctx context.Context, id string, version int64) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Pause(ctx context.Context, id string, from string) error
+	Resume(ctx context.Context, id string, from string) error
+	Cancel(ctx context.Context, id string, endDate string, reason string) error
+	AddPriceChange(ctx context.Context, change domain.PriceChange) error
+	ListPriceChanges(ctx context.Context, subscriptionID string) ([]domain.PriceChange, error)
+	ListEvents(ctx context.Context, subscriptionID string) ([]domain.SubscriptionEvent, error)
+	Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error)
+	TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy domain.TotalGroupBy) ([]domain.TotalBucket, string, error)
+	Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error)
 }
@@ -0,0 +1,90 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"subscription_service/internal/domain"
+)
+
+// Import validates every row like Create and, unless dryRun is set, creates the subscriptions in
+// one transaction. The rows are read and validated first, so the transaction only lasts as long
+// as the inserts and never waits on the client. An error from rows aborts the import, and so do
+// more than domain.MaxImportRows rows. If any row is invalid nothing is imported, but the
+// remaining rows are still checked for the report.
+func (s *Service) Import(ctx context.Context, rows iter.Seq2[domain.ImportRow, error], dryRun bool) (domain.ImportReport, error) {
+	report := domain.ImportReport{DryRun: dryRun}
+
+	var valid []domain.Subscription
+	for row, err := range rows {
+		if err != nil {
+			return domain.ImportReport{}, err
+		}
+		report.Rows++
+		if report.Rows > domain.MaxImportRows {
+			return domain.ImportReport{}, fmt.Errorf("%w: more than %d rows", domain.ErrImportTooLarge, domain.MaxImportRows)
+		}
+
+		if row.Err != nil {
+			report.AddError(row.Line, row.Err)
+			continue
+		}
+
+		sub, err := claim(ctx, row.Subscription)
+		if err == nil {
+			sub, err = validateCreateOrUpdateInput(sub)
+		}
+		if err := withUnreadColumns(row.Invalid, err); err != nil {
+			report.AddError(row.Line, err)
+			continue
+		}
+
+		// Once a row failed nothing is imported, so there is no need to keep the others.
+		if !dryRun && report.Failed == 0 {
+			valid = append(valid, sub)
+		}
+	}
+
+	if dryRun || report.Failed > 0 {
+		return report, nil
+	}
+
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		for _, sub := range valid {
+			if _, err := s.repo.Create(ctx, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	report.Imported = len(valid)
+	return report, nil
+}
+
+// withUnreadColumns adds the columns of a row that could not be read to the errors validating it
+// found. Those columns hold a placeholder, so what validation says about them is dropped.
+func withUnreadColumns(unread domain.FieldErrors, err error) error {
+	if len(unread) == 0 {
+		return err
+	}
+
+	var vErr *domain.ValidationError
+	if err != nil && !errors.As(err, &vErr) {
+		return err
+	}
+
+	errs := append(domain.FieldErrors{}, unread...)
+	for _, fieldErr := range vErr.Fields() {
+		if !slices.ContainsFunc(unread, func(u *domain.FieldError) bool { return u.Field == fieldErr.Field }) {
+			errs = append(errs, &fieldErr)
+		}
+	}
+	return errs.Err()
+}
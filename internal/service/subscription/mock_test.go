@@ -11,8 +11,10 @@ package subscription_test
 
 import (
 	context "context"
+	iter "iter"
 	reflect "reflect"
 	domain "subscription_service/internal/domain"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,34 @@ func (m *Mockrepository) EXPECT() *MockrepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddPriceChange mocks base method.
+func (m *Mockrepository) AddPriceChange(ctx context.Context, change domain.PriceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPriceChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPriceChange indicates an expected call of AddPriceChange.
+func (mr *MockrepositoryMockRecorder) AddPriceChange(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPriceChange", reflect.TypeOf((*Mockrepository)(nil).AddPriceChange), ctx, change)
+}
+
+// Cancel mocks base method.
+func (m *Mockrepository) Cancel(ctx context.Context, id, endDate, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, endDate, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockrepositoryMockRecorder) Cancel(ctx, id, endDate, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*Mockrepository)(nil).Cancel), ctx, id, endDate, reason)
+}
+
 // Create mocks base method.
 func (m *Mockrepository) Create(ctx context.Context, sub domain.Subscription) (string, error) {
 	m.ctrl.T.Helper()
@@ -57,17 +87,31 @@ func (mr *MockrepositoryMockRecorder) Create(ctx, sub any) *gomock.Call {
 }
 
 // Delete mocks base method.
-func (m *Mockrepository) Delete(ctx context.Context, id string) error {
+func (m *Mockrepository) Delete(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockrepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockrepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockrepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*Mockrepository)(nil).Delete), ctx, id, version)
+}
+
+// Export mocks base method.
+func (m *Mockrepository) Export(ctx context.Context, filter domain.ListFilter) iter.Seq2[domain.Subscription, error] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter)
+	ret0, _ := ret[0].(iter.Seq2[domain.Subscription, error])
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockrepositoryMockRecorder) Export(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*Mockrepository)(nil).Export), ctx, filter)
 }
 
 // GetByID mocks base method.
@@ -85,36 +129,185 @@ func (mr *MockrepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*Mockrepository)(nil).GetByID), ctx, id)
 }
 
+// InTx mocks base method.
+func (m *Mockrepository) InTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockrepositoryMockRecorder) InTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*Mockrepository)(nil).InTx), ctx, fn)
+}
+
 // List mocks base method.
-func (m *Mockrepository) List(ctx context.Context, userID, serviceName string) ([]domain.Subscription, error) {
+func (m *Mockrepository) List(ctx context.Context, filter domain.ListFilter, page domain.Pagination) (domain.SubscriptionPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, userID, serviceName)
-	ret0, _ := ret[0].([]domain.Subscription)
+	ret := m.ctrl.Call(m, "List", ctx, filter, page)
+	ret0, _ := ret[0].(domain.SubscriptionPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockrepositoryMockRecorder) List(ctx, userID, serviceName any) *gomock.Call {
+func (mr *MockrepositoryMockRecorder) List(ctx, filter, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mockrepository)(nil).List), ctx, userID, serviceName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*Mockrepository)(nil).List), ctx, filter, page)
 }
 
-// Total mocks base method.
-func (m *Mockrepository) Total(ctx context.Context, filter domain.Subscription) (int64, error) {
+// ListEvents mocks base method.
+func (m *Mockrepository) ListEvents(ctx context.Context, subscriptionID string) ([]domain.SubscriptionEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Total", ctx, filter)
+	ret := m.ctrl.Call(m, "ListEvents", ctx, subscriptionID)
+	ret0, _ := ret[0].([]domain.SubscriptionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockrepositoryMockRecorder) ListEvents(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*Mockrepository)(nil).ListEvents), ctx, subscriptionID)
+}
+
+// ListPriceChanges mocks base method.
+func (m *Mockrepository) ListPriceChanges(ctx context.Context, subscriptionID string) ([]domain.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceChanges", ctx, subscriptionID)
+	ret0, _ := ret[0].([]domain.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriceChanges indicates an expected call of ListPriceChanges.
+func (mr *MockrepositoryMockRecorder) ListPriceChanges(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceChanges", reflect.TypeOf((*Mockrepository)(nil).ListPriceChanges), ctx, subscriptionID)
+}
+
+// Owner mocks base method.
+func (m *Mockrepository) Owner(ctx context.Context, id string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Owner", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Owner indicates an expected call of Owner.
+func (mr *MockrepositoryMockRecorder) Owner(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Owner", reflect.TypeOf((*Mockrepository)(nil).Owner), ctx, id)
+}
+
+// Pause mocks base method.
+func (m *Mockrepository) Pause(ctx context.Context, id, from string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockrepositoryMockRecorder) Pause(ctx, id, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*Mockrepository)(nil).Pause), ctx, id, from)
+}
+
+// Purge mocks base method.
+func (m *Mockrepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletedBefore)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// Purge indicates an expected call of Purge.
+func (mr *MockrepositoryMockRecorder) Purge(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*Mockrepository)(nil).Purge), ctx, deletedBefore)
+}
+
+// Restore mocks base method.
+func (m *Mockrepository) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockrepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*Mockrepository)(nil).Restore), ctx, id)
+}
+
+// Resume mocks base method.
+func (m *Mockrepository) Resume(ctx context.Context, id, from string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockrepositoryMockRecorder) Resume(ctx, id, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*Mockrepository)(nil).Resume), ctx, id, from)
+}
+
+// Timeseries mocks base method.
+func (m *Mockrepository) Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeseries", ctx, filter)
+	ret0, _ := ret[0].([]domain.TimeseriesPoint)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Timeseries indicates an expected call of Timeseries.
+func (mr *MockrepositoryMockRecorder) Timeseries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeseries", reflect.TypeOf((*Mockrepository)(nil).Timeseries), ctx, filter)
+}
+
+// Total mocks base method.
+func (m *Mockrepository) Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Total", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // Total indicates an expected call of Total.
 func (mr *MockrepositoryMockRecorder) Total(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Total", reflect.TypeOf((*Mockrepository)(nil).Total), ctx, filter)
 }
 
+// TotalBreakdown mocks base method.
+func (m *Mockrepository) TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy domain.TotalGroupBy) ([]domain.TotalBucket, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalBreakdown", ctx, filter, groupBy)
+	ret0, _ := ret[0].([]domain.TotalBucket)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TotalBreakdown indicates an expected call of TotalBreakdown.
+func (mr *MockrepositoryMockRecorder) TotalBreakdown(ctx, filter, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalBreakdown", reflect.TypeOf((*Mockrepository)(nil).TotalBreakdown), ctx, filter, groupBy)
+}
+
 // Update mocks base method.
 func (m *Mockrepository) Update(ctx context.Context, sub domain.Subscription) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,71 @@
+package subscription
+
+import (
+	"context"
+
+	"subscription_service/internal/domain"
+)
+
+// restriction returns the user ID the caller may act for, or "" if it may act on every user's
+// subscriptions: admin and staff roles, and calls without a principal such as background jobs. A
+// restricted principal without a user ID may act for no one and is refused with ErrForbidden.
+func restriction(ctx context.Context) (string, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok || p.AllUsers() {
+		return "", nil
+	}
+	if p.UserID == "" {
+		return "", domain.ErrForbidden
+	}
+	return p.UserID, nil
+}
+
+// checkOwner hides a subscription of another user as not found, so that its ID is not revealed.
+func checkOwner(ctx context.Context, sub domain.Subscription) error {
+	userID, err := restriction(ctx)
+	if err != nil {
+		return err
+	}
+	if userID != "" && sub.UserID != userID {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// authorize works like checkOwner for a subscription that has not been read yet. It also covers
+// subscriptions in the trash and purged ones.
+func (s *Service) authorize(ctx context.Context, id string) error {
+	userID, err := restriction(ctx)
+	if err != nil || userID == "" {
+		return err
+	}
+
+	owner, err := s.repo.Owner(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owner != userID {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// claim makes the caller the user of a subscription it writes. A restricted caller may leave the
+// user ID out but not name another user.
+func claim(ctx context.Context, sub domain.Subscription) (domain.Subscription, error) {
+	userID, err := restriction(ctx)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	switch sub.UserID {
+	case userID:
+	case "":
+		sub.UserID = userID
+	default:
+		if userID != "" {
+			return domain.Subscription{}, &domain.ValidationError{Err: domain.NewFieldError("user_id", domain.ErrForeignUserID)}
+		}
+	}
+	return sub, nil
+}
@@ -2,6 +2,8 @@ package subscription
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"subscription_service/internal/domain"
 )
@@ -15,6 +17,11 @@ func New(repo repository) *Service {
 }
 
 func (s *Service) Create(ctx context.Context, sub domain.Subscription) (string, error) {
+	sub, err := claim(ctx, sub)
+	if err != nil {
+		return "", err
+	}
+
 	normalized, err := validateCreateOrUpdateInput(sub)
 	if err != nil {
 		return "", err
@@ -28,16 +35,49 @@ func (s *Service) GetByID(ctx context.Context, id string) (domain.Subscription,
 		return domain.Subscription{}, err
 	}
 
-	return s.repo.GetByID(ctx, id)
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if err := checkOwner(ctx, sub); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	return sub, nil
 }
 
-func (s *Service) List(ctx context.Context, userID string, serviceName string) ([]domain.Subscription, error) {
-	normalizedUserID, normalizedService, err := validateListFilter(userID, serviceName)
+func (s *Service) List(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error) {
+	var err error
+	if filter.OwnerID, err = restriction(ctx); err != nil {
+		return domain.SubscriptionPage{}, err
+	}
+
+	normalized, err := validateListFilter(filter)
+	if err != nil {
+		return domain.SubscriptionPage{}, err
+	}
+
+	pagination, err := validateListPage(page)
+	if err != nil {
+		return domain.SubscriptionPage{}, err
+	}
+
+	return s.repo.List(ctx, normalized, pagination)
+}
+
+// Export streams the subscriptions matching filter, oldest first.
+func (s *Service) Export(ctx context.Context, filter domain.ListFilter) (iter.Seq2[domain.Subscription, error], error) {
+	var err error
+	if filter.OwnerID, err = restriction(ctx); err != nil {
+		return nil, err
+	}
+
+	normalized, err := validateListFilter(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.List(ctx, normalizedUserID, normalizedService)
+	return s.repo.Export(ctx, normalized), nil
 }
 
 func (s *Service) Update(ctx context.Context, sub domain.Subscription) error {
@@ -45,27 +85,259 @@ func (s *Service) Update(ctx context.Context, sub domain.Subscription) error {
 		return err
 	}
 
+	current, err := s.GetByID(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	sub, err = claim(ctx, sub)
+	if err != nil {
+		return err
+	}
+
 	normalized, err := validateCreateOrUpdateInput(sub)
 	if err != nil {
 		return err
 	}
+	if err := checkFinalDates(current, normalized); err != nil {
+		return err
+	}
 
 	return s.repo.Update(ctx, normalized)
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
+// Patch merges a partial update into the stored subscription and validates the result as a whole.
+func (s *Service) Patch(ctx context.Context, id string, patch domain.SubscriptionPatch) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if patch.Version != 0 && patch.Version != sub.Version {
+		return domain.ErrPreconditionFailed
+	}
+
+	// The update is conditional on the version read here, so a change made in between is not lost.
+	patched, err := claim(ctx, patch.Apply(sub))
+	if err != nil {
+		return err
+	}
+	normalized, err := validateCreateOrUpdateInput(patched)
+	if err != nil {
+		return err
+	}
+	if err := checkFinalDates(sub, normalized); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, normalized)
+}
+
+// Delete moves a subscription to the trash. A non-zero version must match the stored one.
+func (s *Service) Delete(ctx context.Context, id string, version int64) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id, version)
+}
+
+// ListTrash lists deleted subscriptions with the same filters and pagination as List.
+func (s *Service) ListTrash(ctx context.Context, filter domain.ListFilter, page domain.PageRequest) (domain.SubscriptionPage, error) {
+	filter.Deleted = true
+	return s.List(ctx, filter, page)
+}
+
+func (s *Service) Restore(ctx context.Context, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repo.Restore(ctx, id)
+}
+
+// PurgeTrash permanently removes subscriptions that have been in the trash for longer than retention.
+func (s *Service) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.Purge(ctx, time.Now().Add(-retention))
+}
+
+func (s *Service) Pause(ctx context.Context, id string, from string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := domain.CheckTransition(sub.Status, domain.StatusPaused); err != nil {
+		return err
+	}
+
+	pausedFrom, err := validatePause(sub, from)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Pause(ctx, id, pausedFrom)
+}
+
+func (s *Service) Resume(ctx context.Context, id string, from string) error {
 	if err := validateID(id); err != nil {
 		return err
 	}
 
-	return s.repo.Delete(ctx, id)
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := domain.CheckTransition(sub.Status, domain.StatusActive); err != nil {
+		return err
+	}
+
+	resumedFrom, err := validateResume(sub, from)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Resume(ctx, id, resumedFrom)
 }
 
-func (s *Service) Total(ctx context.Context, filter domain.Subscription) (int64, error) {
+func (s *Service) Cancel(ctx context.Context, id string, cancellation domain.Cancellation) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := domain.CheckTransition(sub.Status, domain.StatusCancelled); err != nil {
+		return err
+	}
+
+	endDate, err := validateCancel(sub, cancellation)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Cancel(ctx, id, endDate, cancellation.Reason)
+}
+
+func (s *Service) ChangePrice(ctx context.Context, change domain.PriceChange) error {
+	if err := validateID(change.SubscriptionID); err != nil {
+		return err
+	}
+
+	sub, err := s.GetByID(ctx, change.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	normalized, err := validatePriceChange(change, sub)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.AddPriceChange(ctx, normalized)
+}
+
+func (s *Service) ListPriceChanges(ctx context.Context, id string) ([]domain.PriceChange, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListPriceChanges(ctx, id)
+}
+
+// History returns the audit trail of a subscription, oldest first. It stays available after the
+// subscription is purged.
+func (s *Service) History(ctx context.Context, id string) ([]domain.SubscriptionEvent, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		if _, err := s.repo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// Total sums the period's charges and reports the currency of the sum (see domain.TotalFilter).
+func (s *Service) Total(ctx context.Context, filter domain.TotalFilter) (int64, string, error) {
+	var err error
+	if filter.OwnerID, err = restriction(ctx); err != nil {
+		return 0, "", err
+	}
+
 	validated, err := validateTotalFilter(filter)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	return s.repo.Total(ctx, validated)
 }
+
+// TotalBreakdown groups the period's charges. A breakdown by currency reports every bucket in its
+// own currency and no common one.
+func (s *Service) TotalBreakdown(ctx context.Context, filter domain.TotalFilter, groupBy string) ([]domain.TotalBucket, string, error) {
+	var err error
+	if filter.OwnerID, err = restriction(ctx); err != nil {
+		return nil, "", err
+	}
+
+	validated, err := validateTotalFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	group, err := validateGroupBy(groupBy)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.TotalBreakdown(ctx, validated, group)
+}
+
+func (s *Service) Timeseries(ctx context.Context, filter domain.TotalFilter) ([]domain.TimeseriesPoint, string, error) {
+	var err error
+	if filter.OwnerID, err = restriction(ctx); err != nil {
+		return nil, "", err
+	}
+
+	validated, err := validateTotalFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s.repo.Timeseries(ctx, validated)
+}
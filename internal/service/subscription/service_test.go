@@ -2,7 +2,9 @@ package subscription_test
 
 import (
 	"context"
+	"iter"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -23,6 +25,82 @@ func TestServiceCreate_Invalid(t *testing.T) {
 	require.ErrorIs(t, vErr, domain.ErrMissingRequiredFields)
 }
 
+func TestServiceCreate_PriceTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       domain.MaxPrice + 1,
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, err, domain.ErrInvalidPrice)
+	require.Equal(t, "price", vErr.Fields()[0].Field)
+}
+
+func TestServiceCreate_ReportsEveryInvalidField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	endDate, trialEnd := "06-2025", "13-2025"
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       -1,
+		Currency:    "rub",
+		UserID:      "not-a-uuid",
+		StartDate:   "07-2025",
+		EndDate:     &endDate,
+		TrialEnd:    &trialEnd,
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+
+	for _, want := range []error{
+		domain.ErrInvalidPrice, domain.ErrInvalidCurrency, domain.ErrInvalidUserID, domain.ErrInvalidPeriod, domain.ErrInvalidTrialEnd,
+	} {
+		require.ErrorIs(t, err, want)
+	}
+
+	var fields []string
+	for _, field := range vErr.Fields() {
+		fields = append(fields, field.Field+"="+field.Code)
+	}
+	require.Equal(t, []string{
+		"price=invalid_price",
+		"currency=invalid_currency",
+		"user_id=invalid_user_id",
+		"end_date=invalid_period",
+		"trial_end=invalid_trial_end",
+	}, fields)
+}
+
+func TestServiceList_ReportsEveryInvalidFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.List(context.Background(),
+		domain.ListFilter{StartFrom: "09-2025", StartTo: "07-2025", Status: "deleted"},
+		domain.PageRequest{Limit: 1000, Sort: "name"})
+	require.ErrorIs(t, err, domain.ErrInvalidStartRange)
+	require.ErrorIs(t, err, domain.ErrInvalidStatus)
+
+	// The page is only checked once the filter is valid.
+	require.NotErrorIs(t, err, domain.ErrInvalidLimit)
+
+	_, err = svc.List(context.Background(), domain.ListFilter{}, domain.PageRequest{Limit: 1000, Sort: "name"})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.Len(t, vErr.Fields(), 2)
+	require.ErrorIs(t, err, domain.ErrInvalidLimit)
+	require.ErrorIs(t, err, domain.ErrInvalidSort)
+}
+
 func TestServiceCreate_OK(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := NewMockrepository(ctrl)
@@ -41,7 +119,9 @@ func TestServiceCreate_OK(t *testing.T) {
 			require.Equal(t, input.ServiceName, sub.ServiceName)
 			require.Equal(t, input.Price, sub.Price)
 			require.Equal(t, input.UserID, sub.UserID)
-			require.Equal(t, input.StartDate, sub.StartDate)
+			require.Equal(t, "2025-07-01", sub.StartDate)
+			require.Equal(t, domain.DefaultCurrency, sub.Currency)
+			require.Equal(t, domain.BillingMonthly, sub.BillingPeriod)
 			return "id-1", nil
 		})
 
@@ -50,6 +130,40 @@ func TestServiceCreate_OK(t *testing.T) {
 	require.Equal(t, "id-1", id)
 }
 
+func TestServiceCreate_InvalidCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       500,
+		Currency:    "euro",
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidCurrency)
+}
+
+func TestServiceCreate_InvalidBillingPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName:   "iCloud",
+		Price:         1200,
+		BillingPeriod: "daily",
+		UserID:        uuid.NewString(),
+		StartDate:     "07-2025",
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidBillingPeriod)
+}
+
 func TestServiceGetByID_Invalid(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := NewMockrepository(ctrl)
@@ -66,23 +180,210 @@ func TestServiceList_InvalidFilter(t *testing.T) {
 	repo := NewMockrepository(ctrl)
 	svc := subscriptionService.New(repo)
 
-	_, err := svc.List(context.Background(), " bad ", "")
+	_, err := svc.List(context.Background(), domain.ListFilter{UserID: " bad "}, domain.PageRequest{})
 	var vErr *domain.ValidationError
 	require.ErrorAs(t, err, &vErr)
 	require.ErrorIs(t, vErr, domain.ErrInvalidUserID)
 }
 
+func TestServiceList_InvalidRanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	minPrice, maxPrice, negative, huge := 500, 100, -1, domain.MaxPrice+1
+	longTrial := domain.MaxTrialWindowDays + 1
+	tests := []struct {
+		name   string
+		filter domain.ListFilter
+		want   error
+	}{
+		{name: "negative min price", filter: domain.ListFilter{MinPrice: &negative}, want: domain.ErrInvalidMinPrice},
+		{name: "max price out of range", filter: domain.ListFilter{MaxPrice: &huge}, want: domain.ErrInvalidMaxPrice},
+		{name: "min above max", filter: domain.ListFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, want: domain.ErrInvalidPriceRange},
+		{name: "bad active month", filter: domain.ListFilter{ActiveIn: "2025-07"}, want: domain.ErrInvalidActiveMonth},
+		{name: "reversed start range", filter: domain.ListFilter{StartFrom: "09-2025", StartTo: "07-2025"}, want: domain.ErrInvalidStartRange},
+		{name: "open ended with end range", filter: domain.ListFilter{OpenEnded: true, EndTo: "07-2025"}, want: domain.ErrInvalidEndRange},
+		{name: "blank search", filter: domain.ListFilter{Search: "   "}, want: domain.ErrInvalidSearch},
+		{name: "unknown status", filter: domain.ListFilter{Status: "deleted"}, want: domain.ErrInvalidStatus},
+		{name: "negative trial window", filter: domain.ListFilter{TrialEndsWithin: &negative}, want: domain.ErrInvalidTrialWindow},
+		{name: "trial window too long", filter: domain.ListFilter{TrialEndsWithin: &longTrial}, want: domain.ErrInvalidTrialWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.List(context.Background(), tt.filter, domain.PageRequest{})
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, tt.want)
+		})
+	}
+}
+
+func TestServiceList_DefaultPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	repo.EXPECT().List(gomock.Any(), domain.ListFilter{}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ domain.ListFilter, page domain.Pagination) (domain.SubscriptionPage, error) {
+			require.Equal(t, domain.DefaultPageLimit, page.Limit)
+			require.Equal(t, domain.Sort{Field: domain.SortByCreatedAt, Desc: true}, page.Sort)
+			require.Nil(t, page.After)
+			return domain.SubscriptionPage{}, nil
+		})
+
+	_, err := svc.List(context.Background(), domain.ListFilter{}, domain.PageRequest{})
+	require.NoError(t, err)
+}
+
+func TestServiceList_InvalidPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	mismatched := domain.Cursor{Sort: "price", Key: "100", ID: uuid.NewString()}.Encode()
+	tests := []struct {
+		name string
+		page domain.PageRequest
+		want error
+	}{
+		{name: "limit too large", page: domain.PageRequest{Limit: domain.MaxPageLimit + 1}, want: domain.ErrInvalidLimit},
+		{name: "unknown sort", page: domain.PageRequest{Sort: "user_id"}, want: domain.ErrInvalidSort},
+		{name: "garbage cursor", page: domain.PageRequest{Cursor: "%%%"}, want: domain.ErrInvalidCursor},
+		{name: "cursor for another sort", page: domain.PageRequest{Cursor: mismatched, Sort: "-price"}, want: domain.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.List(context.Background(), domain.ListFilter{}, tt.page)
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, tt.want)
+		})
+	}
+}
+
 func TestServiceTotal_InvalidFilter(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := NewMockrepository(ctrl)
 	svc := subscriptionService.New(repo)
 
-	_, err := svc.Total(context.Background(), domain.Subscription{})
+	_, _, err := svc.Total(context.Background(), domain.TotalFilter{})
 	var vErr *domain.ValidationError
 	require.ErrorAs(t, err, &vErr)
 	require.ErrorIs(t, vErr, domain.ErrMissingRequiredFields)
 }
 
+func TestServiceCreate_DayPrecisionDates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	end := "09-2025"
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.Subscription) (string, error) {
+			require.Equal(t, "2025-07-15", sub.StartDate)
+			require.NotNil(t, sub.EndDate)
+			require.Equal(t, "2025-09-30", *sub.EndDate)
+			return "id-1", nil
+		})
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       500,
+		UserID:      uuid.NewString(),
+		StartDate:   "2025-07-15",
+		EndDate:     &end,
+	})
+	require.NoError(t, err)
+}
+
+func TestServiceCreate_InvalidDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       500,
+		UserID:      uuid.NewString(),
+		StartDate:   "2025-02-30",
+	})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidStartDate)
+}
+
+func TestServiceTotal_InvalidProration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, _, err := svc.Total(context.Background(), domain.TotalFilter{From: "07-2025", To: "08-2025", Proration: "hourly"})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidProration)
+}
+
+func TestServiceTotal_CurrencyLeftToRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	// Without a requested currency the repository picks the one the subscriptions are billed in.
+	repo.EXPECT().Total(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter domain.TotalFilter) (int64, string, error) {
+			require.Empty(t, filter.Currency)
+			return 1000, "USD", nil
+		})
+	total, currency, err := svc.Total(context.Background(), domain.TotalFilter{From: "07-2025", To: "08-2025"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), total)
+	require.Equal(t, "USD", currency)
+
+	_, _, err = svc.Total(context.Background(), domain.TotalFilter{From: "07-2025", To: "08-2025", Currency: "usd"})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidCurrency)
+}
+
+func TestServiceTotalBreakdown_InvalidGroupBy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, _, err := svc.TotalBreakdown(context.Background(), domain.TotalFilter{From: "07-2025", To: "08-2025"}, "price")
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidGroupBy)
+}
+
+func TestServicePeriodTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	// Every aggregate is bounded to the same window of domain.MaxPeriodMonths.
+	filter := domain.TotalFilter{From: "01-2025", To: "01-2035"}
+	_, _, err := svc.Total(context.Background(), filter)
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrPeriodTooLong)
+	require.Equal(t, "to", vErr.Fields()[0].Field)
+
+	_, _, err = svc.TotalBreakdown(context.Background(), filter, "month")
+	require.ErrorIs(t, err, domain.ErrPeriodTooLong)
+
+	_, _, err = svc.Timeseries(context.Background(), filter)
+	require.ErrorIs(t, err, domain.ErrPeriodTooLong)
+
+	// Ten years is still allowed.
+	repo.EXPECT().Total(gomock.Any(), gomock.Any()).Return(int64(0), domain.DefaultCurrency, nil)
+	_, _, err = svc.Total(context.Background(), domain.TotalFilter{From: "01-2025", To: "12-2034"})
+	require.NoError(t, err)
+}
+
 func TestServiceUpdate_InvalidID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := NewMockrepository(ctrl)
@@ -94,13 +395,800 @@ func TestServiceUpdate_InvalidID(t *testing.T) {
 	require.ErrorIs(t, vErr, domain.ErrInvalidID)
 }
 
+func TestServicePatch_ClearsEndDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	userID := uuid.NewString()
+	end := "12-2025"
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{
+		ID:            id,
+		ServiceName:   "Netflix",
+		Price:         500,
+		Currency:      "RUB",
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        userID,
+		StartDate:     "07-2025",
+		EndDate:       &end,
+		Status:        domain.StatusActive,
+	}, nil)
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.Subscription) error {
+			require.Equal(t, 600, sub.Price)
+			require.Equal(t, "Netflix", sub.ServiceName)
+			require.Equal(t, userID, sub.UserID)
+			require.Equal(t, "2025-07-01", sub.StartDate)
+			require.Nil(t, sub.EndDate)
+			return nil
+		})
+
+	price := 600
+	var noEnd *string
+	err := svc.Patch(context.Background(), id, domain.SubscriptionPatch{Price: &price, EndDate: &noEnd})
+	require.NoError(t, err)
+}
+
+func TestServicePatch_ValidatesMergedResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	end := "12-2025"
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{
+		ID:          id,
+		ServiceName: "Netflix",
+		Price:       500,
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+		EndDate:     &end,
+	}, nil)
+
+	// The new start date is valid on its own but falls after the stored end date.
+	start := "2026-01-01"
+	err := svc.Patch(context.Background(), id, domain.SubscriptionPatch{StartDate: &start})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidPeriod)
+}
+
+func TestServicePatch_StaleVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{
+		ID:          id,
+		ServiceName: "Netflix",
+		Price:       500,
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+		Version:     4,
+	}, nil).Times(2)
+
+	price := 600
+	err := svc.Patch(context.Background(), id, domain.SubscriptionPatch{Version: 3, Price: &price})
+	require.ErrorIs(t, err, domain.ErrPreconditionFailed)
+
+	// Without If-Match the update is still tied to the version that was merged.
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.Subscription) error {
+			require.Equal(t, int64(4), sub.Version)
+			return nil
+		})
+	require.NoError(t, svc.Patch(context.Background(), id, domain.SubscriptionPatch{Price: &price}))
+}
+
+func TestServiceUpdate_FinalStatusKeepsDates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	end := "12-2025"
+	stored := domain.Subscription{
+		ID:          id,
+		ServiceName: "Netflix",
+		Price:       500,
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+		EndDate:     &end,
+		Status:      domain.StatusCancelled,
+	}
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(stored, nil).Times(4)
+
+	// Moving or clearing the end date would bring the charges back.
+	later := "2026-06-30"
+	updated := stored
+	updated.EndDate = &later
+	var tErr *domain.TransitionError
+	require.ErrorAs(t, svc.Update(context.Background(), updated), &tErr)
+	require.Equal(t, domain.StatusCancelled, tErr.From)
+
+	var noEnd *string
+	err := svc.Patch(context.Background(), id, domain.SubscriptionPatch{EndDate: &noEnd})
+	require.ErrorIs(t, err, domain.ErrInvalidTransition)
+
+	// Other fields may still change, with the dates written in either layout.
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	updated.EndDate = &end
+	updated.StartDate = "2025-07-01"
+	updated.Price = 600
+	require.NoError(t, svc.Update(context.Background(), updated))
+
+	price := 700
+	require.NoError(t, svc.Patch(context.Background(), id, domain.SubscriptionPatch{Price: &price}))
+}
+
 func TestServiceDelete_InvalidID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := NewMockrepository(ctrl)
 	svc := subscriptionService.New(repo)
 
-	err := svc.Delete(context.Background(), "bad")
+	err := svc.Delete(context.Background(), "bad", 0)
 	var vErr *domain.ValidationError
 	require.ErrorAs(t, err, &vErr)
 	require.ErrorIs(t, vErr, domain.ErrInvalidID)
 }
+
+func TestServiceChangePrice_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, StartDate: "07-2025"}, nil)
+	repo.EXPECT().AddPriceChange(gomock.Any(), domain.PriceChange{
+		SubscriptionID: id,
+		Price:          600,
+		EffectiveFrom:  "2025-09-01",
+	}).Return(nil)
+
+	err := svc.ChangePrice(context.Background(), domain.PriceChange{SubscriptionID: id, Price: 600, EffectiveFrom: "09-2025"})
+	require.NoError(t, err)
+}
+
+func TestServiceChangePrice_OutsideSubscription(t *testing.T) {
+	end := "2025-12-31"
+	cases := []struct {
+		name          string
+		effectiveFrom string
+	}{
+		{name: "before start", effectiveFrom: "06-2025"},
+		{name: "after end", effectiveFrom: "2026-01-01"},
+		{name: "malformed", effectiveFrom: "2025-13-01"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := subscriptionService.New(repo)
+
+			id := uuid.NewString()
+			repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, StartDate: "07-2025", EndDate: &end}, nil)
+
+			err := svc.ChangePrice(context.Background(), domain.PriceChange{SubscriptionID: id, Price: 600, EffectiveFrom: tc.effectiveFrom})
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, domain.ErrInvalidEffectiveFrom)
+		})
+	}
+}
+
+func TestServicePause_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	resumed := "09-2025"
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{
+		ID:        id,
+		StartDate: "01-2025",
+		Status:    domain.StatusActive,
+		Pauses:    []domain.Pause{{PausedFrom: "06-2025", ResumedFrom: &resumed}},
+	}, nil)
+	repo.EXPECT().Pause(gomock.Any(), id, "2025-12-01").Return(nil)
+
+	require.NoError(t, svc.Pause(context.Background(), id, "12-2025"))
+}
+
+func TestServicePause_Invalid(t *testing.T) {
+	resumed := "09-2025"
+	cases := []struct {
+		name    string
+		pauses  []domain.Pause
+		from    string
+		wantErr error
+	}{
+		{name: "before start", from: "12-2024", wantErr: domain.ErrInvalidPauseDate},
+		{name: "overlaps previous pause", pauses: []domain.Pause{{PausedFrom: "06-2025", ResumedFrom: &resumed}}, from: "08-2025", wantErr: domain.ErrInvalidPauseDate},
+		{name: "already paused", pauses: []domain.Pause{{PausedFrom: "06-2025"}}, from: "08-2025", wantErr: domain.ErrSubscriptionPaused},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := subscriptionService.New(repo)
+
+			id := uuid.NewString()
+			repo.EXPECT().GetByID(gomock.Any(), id).
+				Return(domain.Subscription{ID: id, StartDate: "01-2025", Status: domain.StatusActive, Pauses: tc.pauses}, nil)
+
+			err := svc.Pause(context.Background(), id, tc.from)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestServiceResume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, StartDate: "01-2025", Status: domain.StatusActive}, nil)
+	require.ErrorIs(t, svc.Resume(context.Background(), id, "09-2025"), domain.ErrSubscriptionNotPaused)
+
+	repo.EXPECT().GetByID(gomock.Any(), id).
+		Return(domain.Subscription{ID: id, StartDate: "01-2025", Status: domain.StatusPaused, Pauses: []domain.Pause{{PausedFrom: "06-2025"}}}, nil).
+		Times(2)
+
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, svc.Resume(context.Background(), id, "06-2025"), &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidResumeDate)
+
+	repo.EXPECT().Resume(gomock.Any(), id, "2025-09-01").Return(nil)
+	require.NoError(t, svc.Resume(context.Background(), id, "09-2025"))
+}
+
+func TestServiceCreate_Trial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	trialEnd := "07-2025"
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.Subscription) (string, error) {
+			require.Equal(t, 0, sub.Price)
+			require.NotNil(t, sub.TrialEnd)
+			require.Equal(t, "2025-07-31", *sub.TrialEnd)
+			return "id-1", nil
+		})
+
+	_, err := svc.Create(context.Background(), domain.Subscription{
+		ServiceName: "Netflix",
+		UserID:      uuid.NewString(),
+		StartDate:   "07-2025",
+		TrialEnd:    &trialEnd,
+	})
+	require.NoError(t, err)
+}
+
+func TestServiceCreate_InvalidTrialEnd(t *testing.T) {
+	end := "2025-08-15"
+	cases := []struct {
+		name     string
+		trialEnd string
+	}{
+		{name: "before start", trialEnd: "2025-06-30"},
+		{name: "after end", trialEnd: "08-2025"},
+		{name: "malformed", trialEnd: "2025-08"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := subscriptionService.New(repo)
+
+			_, err := svc.Create(context.Background(), domain.Subscription{
+				ServiceName: "Netflix",
+				Price:       500,
+				UserID:      uuid.NewString(),
+				StartDate:   "07-2025",
+				EndDate:     &end,
+				TrialEnd:    &tc.trialEnd,
+			})
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, domain.ErrInvalidTrialEnd)
+		})
+	}
+}
+
+func TestServicePause_Cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, StartDate: "01-2025", Status: domain.StatusCancelled}, nil)
+
+	err := svc.Pause(context.Background(), id, "06-2025")
+	var tErr *domain.TransitionError
+	require.ErrorAs(t, err, &tErr)
+	require.Equal(t, domain.StatusCancelled, tErr.From)
+	require.Equal(t, domain.StatusPaused, tErr.To)
+	require.ErrorIs(t, err, domain.ErrInvalidTransition)
+}
+
+func TestServiceCancel_OK(t *testing.T) {
+	end := "2025-12-15"
+	cases := []struct {
+		name    string
+		endDate *string
+		month   string
+		want    string
+	}{
+		{name: "open ended", month: "09-2025", want: "2025-09-30"},
+		{name: "earlier than end date", endDate: &end, month: "09-2025", want: "2025-09-30"},
+		{name: "month of end date", endDate: &end, month: "12-2025", want: "2025-12-15"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := subscriptionService.New(repo)
+
+			id := uuid.NewString()
+			repo.EXPECT().GetByID(gomock.Any(), id).
+				Return(domain.Subscription{ID: id, StartDate: "01-2025", EndDate: tc.endDate, Status: domain.StatusPaused}, nil)
+			repo.EXPECT().Cancel(gomock.Any(), id, tc.want, "too expensive").Return(nil)
+
+			err := svc.Cancel(context.Background(), id, domain.Cancellation{EffectiveMonth: tc.month, Reason: "too expensive"})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceCancel_Invalid(t *testing.T) {
+	end := "2025-12-15"
+	cases := []struct {
+		name    string
+		status  domain.SubscriptionStatus
+		month   string
+		wantErr error
+	}{
+		{name: "already cancelled", status: domain.StatusCancelled, month: "09-2025", wantErr: domain.ErrInvalidTransition},
+		{name: "expired", status: domain.StatusExpired, month: "09-2025", wantErr: domain.ErrInvalidTransition},
+		{name: "before start", status: domain.StatusActive, month: "12-2024", wantErr: domain.ErrInvalidCancelMonth},
+		{name: "after end", status: domain.StatusActive, month: "01-2026", wantErr: domain.ErrInvalidCancelMonth},
+		{name: "day instead of month", status: domain.StatusActive, month: "2025-09-01", wantErr: domain.ErrInvalidCancelMonth},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := NewMockrepository(ctrl)
+			svc := subscriptionService.New(repo)
+
+			id := uuid.NewString()
+			repo.EXPECT().GetByID(gomock.Any(), id).
+				Return(domain.Subscription{ID: id, StartDate: "01-2025", EndDate: &end, Status: tc.status}, nil)
+
+			err := svc.Cancel(context.Background(), id, domain.Cancellation{EffectiveMonth: tc.month})
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestServiceListTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	repo.EXPECT().List(gomock.Any(), domain.ListFilter{ServiceName: "Netflix", Deleted: true}, gomock.Any()).
+		Return(domain.SubscriptionPage{}, nil)
+
+	_, err := svc.ListTrash(context.Background(), domain.ListFilter{ServiceName: "Netflix"}, domain.PageRequest{})
+	require.NoError(t, err)
+}
+
+func TestServicePurgeTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	before := time.Now().Add(-48 * time.Hour)
+	repo.EXPECT().Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deletedBefore time.Time) (int64, error) {
+			require.WithinDuration(t, before, deletedBefore, time.Minute)
+			return 2, nil
+		})
+
+	purged, err := svc.PurgeTrash(context.Background(), 48*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+}
+
+func TestServiceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	events := []domain.SubscriptionEvent{{ID: 1, SubscriptionID: id, Action: domain.EventPurge}}
+	repo.EXPECT().ListEvents(gomock.Any(), id).Return(events, nil)
+
+	got, err := svc.History(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+}
+
+func TestServiceHistory_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().ListEvents(gomock.Any(), id).Return([]domain.SubscriptionEvent{}, nil)
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+
+	_, err := svc.History(context.Background(), id)
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+
+	_, err = svc.History(context.Background(), "not-a-uuid")
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+}
+
+func batchCreate(price int) domain.BatchOperation {
+	return domain.BatchOperation{
+		Action: domain.BatchCreate,
+		Subscription: domain.Subscription{
+			ServiceName: "Netflix",
+			Price:       price,
+			UserID:      uuid.NewString(),
+			StartDate:   "07-2025",
+		},
+	}
+}
+
+func TestServiceBatch_BestEffort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id := uuid.NewString()
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return("id-1", nil)
+	repo.EXPECT().Delete(gomock.Any(), id, int64(2)).Return(domain.ErrSubscriptionNotFound)
+
+	results, err := svc.Batch(context.Background(), domain.BatchBestEffort, []domain.BatchOperation{
+		batchCreate(500),
+		batchCreate(-1),
+		{Action: domain.BatchDelete, ID: id, Version: 2},
+		{Action: "upsert"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	require.Equal(t, domain.BatchStatusOK, results[0].Status)
+	require.Equal(t, "id-1", results[0].ID)
+	require.Equal(t, domain.BatchStatusFailed, results[1].Status)
+	require.ErrorIs(t, results[1].Err, domain.ErrInvalidPrice)
+	require.Equal(t, domain.BatchStatusFailed, results[2].Status)
+	require.ErrorIs(t, results[2].Err, domain.ErrSubscriptionNotFound)
+	require.ErrorIs(t, results[3].Err, domain.ErrInvalidBatchAction)
+}
+
+func TestServiceBatch_Atomic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	var rolledBack bool
+	repo.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			err := fn(ctx)
+			rolledBack = err != nil
+			return err
+		})
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return("id-1", nil)
+
+	results, err := svc.Batch(context.Background(), "", []domain.BatchOperation{
+		batchCreate(500),
+		batchCreate(-1),
+		batchCreate(700),
+	})
+	require.NoError(t, err)
+	require.True(t, rolledBack)
+
+	require.Equal(t, domain.BatchStatusRolledBack, results[0].Status)
+	require.Empty(t, results[0].ID)
+	require.Equal(t, domain.BatchStatusFailed, results[1].Status)
+	require.ErrorIs(t, results[1].Err, domain.ErrInvalidPrice)
+	require.Equal(t, domain.BatchStatusSkipped, results[2].Status)
+}
+
+func TestServiceBatch_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := subscriptionService.New(NewMockrepository(ctrl))
+
+	tests := []struct {
+		name string
+		mode domain.BatchMode
+		ops  []domain.BatchOperation
+		want error
+	}{
+		{name: "empty", mode: domain.BatchAtomic, want: domain.ErrInvalidBatchSize},
+		{name: "too large", mode: domain.BatchAtomic, ops: make([]domain.BatchOperation, domain.MaxBatchSize+1), want: domain.ErrInvalidBatchSize},
+		{name: "mode", mode: "eventually", ops: []domain.BatchOperation{batchCreate(500)}, want: domain.ErrInvalidBatchMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Batch(context.Background(), tt.mode, tt.ops)
+			var vErr *domain.ValidationError
+			require.ErrorAs(t, err, &vErr)
+			require.ErrorIs(t, vErr, tt.want)
+		})
+	}
+}
+
+func importRows(rows ...domain.ImportRow) iter.Seq2[domain.ImportRow, error] {
+	return func(yield func(domain.ImportRow, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func importRow(line int, price int) domain.ImportRow {
+	return domain.ImportRow{Line: line, Subscription: batchCreate(price).Subscription}
+}
+
+func TestServiceImport_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	repo.EXPECT().InTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) })
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).Return("id", nil).Times(2)
+
+	report, err := svc.Import(context.Background(), importRows(importRow(2, 500), importRow(3, 700)), false)
+	require.NoError(t, err)
+	require.Equal(t, domain.ImportReport{Rows: 2, Imported: 2}, report)
+}
+
+func TestServiceImport_InvalidRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	// Every row is validated before the transaction, so an invalid one means no write at all.
+	unreadable := domain.ImportRow{Line: 4, Err: &domain.ValidationError{Err: domain.ErrInvalidPrice}}
+	report, err := svc.Import(context.Background(), importRows(importRow(2, 500), importRow(3, -1), unreadable, importRow(5, 500)), false)
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Rows)
+	require.Zero(t, report.Imported)
+	require.Equal(t, 2, report.Failed)
+	require.Len(t, report.Errors, 2)
+	require.Equal(t, 3, report.Errors[0].Line)
+	require.ErrorIs(t, report.Errors[0].Err, domain.ErrInvalidPrice)
+	require.Equal(t, 4, report.Errors[1].Line)
+}
+
+func TestServiceImport_ReportsEveryInvalidColumn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := subscriptionService.New(NewMockrepository(ctrl))
+
+	// The price could not be read, and the start date is invalid as well.
+	row := importRow(2, 0)
+	row.Subscription.StartDate = "2025-13"
+	row.Invalid.Add("price", domain.ErrInvalidPrice)
+
+	report, err := svc.Import(context.Background(), importRows(row), true)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Failed)
+
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, report.Errors[0].Err, &vErr)
+	fields := vErr.Fields()
+	require.Len(t, fields, 2)
+	require.Equal(t, "price", fields[0].Field)
+	require.Equal(t, "start_date", fields[1].Field)
+}
+
+func TestServiceImport_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := subscriptionService.New(NewMockrepository(ctrl))
+
+	report, err := svc.Import(context.Background(), importRows(importRow(2, 500), importRow(3, -1)), true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 2, report.Rows)
+	require.Zero(t, report.Imported)
+	require.Equal(t, 1, report.Failed)
+	require.Equal(t, 3, report.Errors[0].Line)
+}
+
+func TestServiceImport_TooManyRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := subscriptionService.New(NewMockrepository(ctrl))
+
+	rows := func(yield func(domain.ImportRow, error) bool) {
+		for line := 2; line <= domain.MaxImportRows+2; line++ {
+			if !yield(importRow(line, 500), nil) {
+				return
+			}
+		}
+	}
+
+	_, err := svc.Import(context.Background(), rows, false)
+	require.ErrorIs(t, err, domain.ErrImportTooLarge)
+}
+
+func TestServiceImport_ReadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := subscriptionService.New(NewMockrepository(ctrl))
+
+	readErr := &domain.ValidationError{Err: domain.ErrInvalidCSV}
+	rows := func(yield func(domain.ImportRow, error) bool) {
+		if yield(importRow(2, 500), nil) {
+			yield(domain.ImportRow{}, readErr)
+		}
+	}
+
+	_, err := svc.Import(context.Background(), rows, true)
+	require.ErrorIs(t, err, domain.ErrInvalidCSV)
+}
+
+func TestServiceExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	_, err := svc.Export(context.Background(), domain.ListFilter{Status: "deleted"})
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrInvalidStatus)
+
+	subs := func(yield func(domain.Subscription, error) bool) {
+		yield(domain.Subscription{ID: "id-1"}, nil)
+	}
+	repo.EXPECT().Export(gomock.Any(), domain.ListFilter{ServiceName: "Netflix"}).Return(subs)
+
+	seq, err := svc.Export(context.Background(), domain.ListFilter{ServiceName: "Netflix"})
+	require.NoError(t, err)
+	for sub, err := range seq {
+		require.NoError(t, err)
+		require.Equal(t, "id-1", sub.ID)
+	}
+}
+
+func principalContext(userID string, roles ...string) context.Context {
+	return domain.WithPrincipal(context.Background(), domain.Principal{UserID: userID, Roles: roles})
+}
+
+func TestServiceList_RestrictedToPrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	userID, other := uuid.NewString(), uuid.NewString()
+	repo.EXPECT().List(gomock.Any(), domain.ListFilter{UserID: other, OwnerID: userID}, gomock.Any()).
+		Return(domain.SubscriptionPage{}, nil)
+	repo.EXPECT().Total(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter domain.TotalFilter) (int64, string, error) {
+			require.Equal(t, userID, filter.OwnerID)
+			return 0, domain.DefaultCurrency, nil
+		})
+
+	// A caller cannot widen the restriction by setting it in the filter.
+	_, err := svc.List(principalContext(userID), domain.ListFilter{UserID: other, OwnerID: other}, domain.PageRequest{})
+	require.NoError(t, err)
+
+	_, _, err = svc.Total(principalContext(userID), domain.TotalFilter{From: "07-2025", To: "08-2025"})
+	require.NoError(t, err)
+
+	// Admins see every user's subscriptions.
+	repo.EXPECT().List(gomock.Any(), domain.ListFilter{UserID: other}, gomock.Any()).
+		Return(domain.SubscriptionPage{}, nil)
+	_, err = svc.List(principalContext(userID, domain.RoleAdmin), domain.ListFilter{UserID: other}, domain.PageRequest{})
+	require.NoError(t, err)
+
+	// So does support staff.
+	repo.EXPECT().List(gomock.Any(), domain.ListFilter{UserID: other}, gomock.Any()).
+		Return(domain.SubscriptionPage{}, nil)
+	_, err = svc.List(principalContext(userID, domain.RoleSupport), domain.ListFilter{UserID: other}, domain.PageRequest{})
+	require.NoError(t, err)
+}
+
+func TestServiceGetByID_OtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id, userID, other := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, UserID: other}, nil).Times(3)
+
+	_, err := svc.GetByID(principalContext(userID), id)
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+
+	require.ErrorIs(t, svc.Pause(principalContext(userID), id, "08-2025"), domain.ErrSubscriptionNotFound)
+
+	sub, err := svc.GetByID(principalContext(userID, domain.RoleAdmin), id)
+	require.NoError(t, err)
+	require.Equal(t, other, sub.UserID)
+}
+
+func TestServiceDelete_OtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	id, userID := uuid.NewString(), uuid.NewString()
+	repo.EXPECT().Owner(gomock.Any(), id).Return(uuid.NewString(), nil)
+	repo.EXPECT().GetByID(gomock.Any(), id).Return(domain.Subscription{ID: id, UserID: uuid.NewString()}, nil)
+
+	require.ErrorIs(t, svc.Delete(principalContext(userID), id, 0), domain.ErrSubscriptionNotFound)
+	require.ErrorIs(t, svc.Update(principalContext(userID), domain.Subscription{ID: id}), domain.ErrSubscriptionNotFound)
+
+	repo.EXPECT().Owner(gomock.Any(), id).Return(userID, nil)
+	repo.EXPECT().Delete(gomock.Any(), id, int64(0)).Return(nil)
+	require.NoError(t, svc.Delete(principalContext(userID), id, 0))
+}
+
+func TestServiceCreate_ClaimsUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	userID := uuid.NewString()
+	sub := domain.Subscription{
+		ServiceName: "Netflix",
+		Price:       500,
+		StartDate:   "07-2025",
+	}
+
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, created domain.Subscription) (string, error) {
+			require.Equal(t, userID, created.UserID)
+			return uuid.NewString(), nil
+		})
+	_, err := svc.Create(principalContext(userID), sub)
+	require.NoError(t, err)
+
+	sub.UserID = uuid.NewString()
+	_, err = svc.Create(principalContext(userID), sub)
+	var vErr *domain.ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.ErrorIs(t, vErr, domain.ErrForeignUserID)
+	require.Equal(t, "user_id", vErr.Fields()[0].Field)
+	require.Equal(t, "foreign_user_id", vErr.Fields()[0].Code)
+}
+
+func TestServiceRestrictedWithoutUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockrepository(ctrl)
+	svc := subscriptionService.New(repo)
+
+	// A principal limited to its own subscriptions but without a user ID must not see everyone's.
+	ctx := domain.WithPrincipal(context.Background(), domain.Principal{Scopes: []string{string(domain.PermReportsRead)}})
+
+	_, err := svc.List(ctx, domain.ListFilter{}, domain.PageRequest{})
+	require.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, err = svc.Export(ctx, domain.ListFilter{})
+	require.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, _, err = svc.Total(ctx, domain.TotalFilter{From: "07-2025", To: "08-2025"})
+	require.ErrorIs(t, err, domain.ErrForbidden)
+
+	_, _, err = svc.Timeseries(ctx, domain.TotalFilter{From: "07-2025", To: "08-2025"})
+	require.ErrorIs(t, err, domain.ErrForbidden)
+
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(domain.Subscription{UserID: uuid.NewString()}, nil)
+	_, err = svc.GetByID(ctx, uuid.NewString())
+	require.ErrorIs(t, err, domain.ErrForbidden)
+}
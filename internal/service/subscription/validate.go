@@ -1,6 +1,7 @@
 package subscription
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,129 +10,561 @@ import (
 	"subscription_service/internal/domain"
 )
 
-const monthYearLayout = "01-2006"
+const (
+	monthYearLayout = "01-2006"
+	dateLayout      = "2006-01-02"
+)
 
+// validateCreateOrUpdateInput checks every field of sub and reports all invalid ones at once.
 func validateCreateOrUpdateInput(sub domain.Subscription) (domain.Subscription, error) {
-	if strings.TrimSpace(sub.ServiceName) == "" || strings.TrimSpace(sub.UserID) == "" || strings.TrimSpace(sub.StartDate) == "" {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrMissingRequiredFields}
+	var errs domain.FieldErrors
+
+	switch {
+	case strings.TrimSpace(sub.ServiceName) == "":
+		errs.Add("service_name", domain.ErrMissingRequiredFields)
+	case strings.TrimSpace(sub.ServiceName) != sub.ServiceName:
+		errs.Add("service_name", domain.ErrInvalidServiceName)
 	}
 
-	if strings.TrimSpace(sub.ServiceName) != sub.ServiceName {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidServiceName}
+	// A trial may be recorded before the price after it is known.
+	if sub.Price < 0 || sub.Price > domain.MaxPrice || (sub.Price == 0 && sub.TrialEnd == nil) {
+		errs.Add("price", domain.ErrInvalidPrice)
 	}
 
-	if sub.Price <= 0 {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidPrice}
+	if sub.Currency == "" {
+		sub.Currency = domain.DefaultCurrency
+	}
+	if !domain.IsCurrencyCode(sub.Currency) {
+		errs.Add("currency", domain.ErrInvalidCurrency)
 	}
 
-	if _, err := uuid.Parse(sub.UserID); err != nil {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidUserID}
+	switch sub.BillingPeriod {
+	case "":
+		sub.BillingPeriod = domain.BillingMonthly
+	case domain.BillingWeekly, domain.BillingMonthly, domain.BillingQuarterly, domain.BillingYearly:
+	default:
+		errs.Add("billing_period", domain.ErrInvalidBillingPeriod)
 	}
 
-	startDate, err := parseMonthYear(sub.StartDate)
-	if err != nil {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidStartDate}
+	if strings.TrimSpace(sub.UserID) == "" {
+		errs.Add("user_id", domain.ErrMissingRequiredFields)
+	} else if _, err := uuid.Parse(sub.UserID); err != nil {
+		errs.Add("user_id", domain.ErrInvalidUserID)
+	}
+
+	// The dates are checked against each other only once each of them parses.
+	var startDate, endDate time.Time
+	startOK, endOK := false, false
+
+	if strings.TrimSpace(sub.StartDate) == "" {
+		errs.Add("start_date", domain.ErrMissingRequiredFields)
+	} else if parsed, err := parseDate(sub.StartDate, false); err != nil {
+		errs.Add("start_date", domain.ErrInvalidStartDate)
+	} else {
+		startDate, startOK = parsed, true
+		sub.StartDate = startDate.Format(dateLayout)
 	}
-	sub.StartDate = startDate.Format(monthYearLayout)
 
 	if sub.EndDate != nil {
-		if strings.TrimSpace(*sub.EndDate) == "" {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidEndDate}
-		}
-		if strings.TrimSpace(*sub.EndDate) != *sub.EndDate {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidEndDate}
+		parsed, err := parseDate(*sub.EndDate, true)
+		switch {
+		case strings.TrimSpace(*sub.EndDate) != *sub.EndDate || err != nil:
+			errs.Add("end_date", domain.ErrInvalidEndDate)
+		case startOK && parsed.Before(startDate):
+			errs.Add("end_date", domain.ErrInvalidPeriod)
+		default:
+			endDate, endOK = parsed, true
+			formatted := endDate.Format(dateLayout)
+			sub.EndDate = &formatted
 		}
+	}
 
-		endDate, err := parseMonthYear(*sub.EndDate)
-		if err != nil {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidEndDate}
-		}
-		if endDate.Before(startDate) {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidPeriod}
+	if sub.TrialEnd != nil {
+		trialEnd, err := parseDate(*sub.TrialEnd, true)
+		switch {
+		case err != nil, startOK && trialEnd.Before(startDate), endOK && trialEnd.After(endDate):
+			errs.Add("trial_end", domain.ErrInvalidTrialEnd)
+		default:
+			formatted := trialEnd.Format(dateLayout)
+			sub.TrialEnd = &formatted
 		}
-		formatted := endDate.Format(monthYearLayout)
-		sub.EndDate = &formatted
 	}
 
+	if err := errs.Err(); err != nil {
+		return domain.Subscription{}, err
+	}
 	return sub, nil
 }
 
+// checkFinalDates rejects an update that moves the dates of a cancelled or expired subscription.
+// Charges follow the dates, so such a change would bill it again while it still reads as final.
+func checkFinalDates(current domain.Subscription, updated domain.Subscription) error {
+	err := domain.CheckTransition(current.Status, domain.StatusActive)
+	if err == nil {
+		return nil
+	}
+	if sameDate(&current.StartDate, &updated.StartDate, false) &&
+		sameDate(current.EndDate, updated.EndDate, true) &&
+		sameDate(current.TrialEnd, updated.TrialEnd, true) {
+		return nil
+	}
+	return err
+}
+
+// sameDate reports whether two optional dates, in any accepted layout, name the same day.
+func sameDate(a *string, b *string, endOfMonth bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	left, errLeft := parseDate(*a, endOfMonth)
+	right, errRight := parseDate(*b, endOfMonth)
+	return errLeft == nil && errRight == nil && left.Equal(right)
+}
+
 func validateID(id string) error {
 	if _, err := uuid.Parse(id); err != nil {
-		return &domain.ValidationError{Err: domain.ErrInvalidID}
+		return &domain.ValidationError{Err: domain.NewFieldError("id", domain.ErrInvalidID)}
 	}
 	return nil
 }
 
-func validateListFilter(userID string, serviceName string) (string, string, error) {
-	if userID != "" {
-		if strings.TrimSpace(userID) != userID {
-			return "", "", &domain.ValidationError{Err: domain.ErrInvalidUserID}
+// validatePriceChange checks a price change against the subscription it applies to: the change
+// must take effect while the subscription is active.
+func validatePriceChange(change domain.PriceChange, sub domain.Subscription) (domain.PriceChange, error) {
+	var errs domain.FieldErrors
+
+	if change.Price <= 0 || change.Price > domain.MaxPrice {
+		errs.Add("price", domain.ErrInvalidPrice)
+	}
+
+	if strings.TrimSpace(change.EffectiveFrom) == "" {
+		errs.Add("effective_from", domain.ErrMissingRequiredFields)
+		return domain.PriceChange{}, errs.Err()
+	}
+
+	effectiveFrom, err := parseDate(change.EffectiveFrom, false)
+	if err != nil {
+		errs.Add("effective_from", domain.ErrInvalidEffectiveFrom)
+		return domain.PriceChange{}, errs.Err()
+	}
+
+	startDate, err := parseDate(sub.StartDate, false)
+	if err != nil {
+		return domain.PriceChange{}, err
+	}
+	outside := effectiveFrom.Before(startDate)
+
+	if sub.EndDate != nil {
+		endDate, err := parseDate(*sub.EndDate, true)
+		if err != nil {
+			return domain.PriceChange{}, err
+		}
+		outside = outside || effectiveFrom.After(endDate)
+	}
+
+	if outside {
+		errs.Add("effective_from", domain.ErrInvalidEffectiveFrom)
+	}
+	if err := errs.Err(); err != nil {
+		return domain.PriceChange{}, err
+	}
+
+	change.EffectiveFrom = effectiveFrom.Format(dateLayout)
+	return change, nil
+}
+
+// validatePause checks that a subscription can be paused from the given day: it is not paused
+// already, the day falls within the subscription and it does not precede an earlier pause.
+func validatePause(sub domain.Subscription, from string) (string, error) {
+	if strings.TrimSpace(from) == "" {
+		return "", fromError(domain.ErrMissingRequiredFields)
+	}
+
+	pausedFrom, err := parseDate(from, false)
+	if err != nil {
+		return "", fromError(domain.ErrInvalidPauseDate)
+	}
+
+	startDate, err := parseDate(sub.StartDate, false)
+	if err != nil {
+		return "", err
+	}
+	if pausedFrom.Before(startDate) {
+		return "", fromError(domain.ErrInvalidPauseDate)
+	}
+
+	if sub.EndDate != nil {
+		endDate, err := parseDate(*sub.EndDate, true)
+		if err != nil {
+			return "", err
 		}
-		if _, err := uuid.Parse(userID); err != nil {
-			return "", "", &domain.ValidationError{Err: domain.ErrInvalidUserID}
+		if pausedFrom.After(endDate) {
+			return "", fromError(domain.ErrInvalidPauseDate)
 		}
 	}
 
-	if serviceName != "" {
-		if strings.TrimSpace(serviceName) == "" || strings.TrimSpace(serviceName) != serviceName {
-			return "", "", &domain.ValidationError{Err: domain.ErrInvalidServiceName}
+	if n := len(sub.Pauses); n > 0 {
+		last := sub.Pauses[n-1]
+		if last.ResumedFrom == nil {
+			return "", domain.ErrSubscriptionPaused
+		}
+		resumedFrom, err := parseDate(*last.ResumedFrom, false)
+		if err != nil {
+			return "", err
+		}
+		if pausedFrom.Before(resumedFrom) {
+			return "", fromError(domain.ErrInvalidPauseDate)
 		}
 	}
 
-	return userID, serviceName, nil
+	return pausedFrom.Format(dateLayout), nil
 }
 
-func validateTotalFilter(filter domain.Subscription) (domain.Subscription, error) {
-	if strings.TrimSpace(filter.StartDate) == "" || filter.EndDate == nil || strings.TrimSpace(*filter.EndDate) == "" {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrMissingRequiredFields}
+// validateResume checks that a subscription is paused and can be resumed on the given day.
+func validateResume(sub domain.Subscription, from string) (string, error) {
+	if strings.TrimSpace(from) == "" {
+		return "", fromError(domain.ErrMissingRequiredFields)
+	}
+
+	resumedFrom, err := parseDate(from, false)
+	if err != nil {
+		return "", fromError(domain.ErrInvalidResumeDate)
 	}
 
-	if strings.TrimSpace(filter.StartDate) != filter.StartDate {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidFromDate}
+	n := len(sub.Pauses)
+	if n == 0 || sub.Pauses[n-1].ResumedFrom != nil {
+		return "", domain.ErrSubscriptionNotPaused
 	}
 
-	fromDate, err := parseMonthYear(filter.StartDate)
+	pausedFrom, err := parseDate(sub.Pauses[n-1].PausedFrom, false)
 	if err != nil {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidFromDate}
+		return "", err
+	}
+	if !resumedFrom.After(pausedFrom) {
+		return "", fromError(domain.ErrInvalidResumeDate)
+	}
+
+	return resumedFrom.Format(dateLayout), nil
+}
+
+// fromError reports err on the from field of a pause or a resume.
+func fromError(err error) error {
+	return &domain.ValidationError{Err: domain.NewFieldError("from", err)}
+}
+
+// validateCancel checks a cancellation and returns the new end date: the last day of the
+// effective month, or the current end date if the subscription ends earlier in that month.
+func validateCancel(sub domain.Subscription, cancellation domain.Cancellation) (string, error) {
+	var errs domain.FieldErrors
+
+	if len([]rune(cancellation.Reason)) > domain.MaxCancelReasonLength {
+		errs.Add("reason", domain.ErrInvalidCancelReason)
+	}
+
+	if strings.TrimSpace(cancellation.EffectiveMonth) == "" {
+		errs.Add("effective_month", domain.ErrMissingRequiredFields)
+		return "", errs.Err()
 	}
 
-	if strings.TrimSpace(*filter.EndDate) != *filter.EndDate {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidToDate}
+	month, err := parseMonthYear(cancellation.EffectiveMonth)
+	if err != nil {
+		errs.Add("effective_month", domain.ErrInvalidCancelMonth)
+		return "", errs.Err()
+	}
+	if err := errs.Err(); err != nil {
+		return "", err
 	}
+	endDate := month.AddDate(0, 1, -1)
 
-	toDate, err := parseMonthYear(*filter.EndDate)
+	startDate, err := parseDate(sub.StartDate, false)
 	if err != nil {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidToDate}
+		return "", err
+	}
+	if endDate.Before(startDate) {
+		return "", &domain.ValidationError{Err: domain.NewFieldError("effective_month", domain.ErrInvalidCancelMonth)}
+	}
+
+	if sub.EndDate != nil {
+		currentEnd, err := parseDate(*sub.EndDate, true)
+		if err != nil {
+			return "", err
+		}
+		// A cancellation may end a subscription earlier but never extend it.
+		if month.After(currentEnd) {
+			return "", &domain.ValidationError{Err: domain.NewFieldError("effective_month", domain.ErrInvalidCancelMonth)}
+		}
+		if endDate.After(currentEnd) {
+			endDate = currentEnd
+		}
+	}
+
+	return endDate.Format(dateLayout), nil
+}
+
+func validateListFilter(filter domain.ListFilter) (domain.ListFilter, error) {
+	var errs domain.FieldErrors
+
+	if filter.UserID != "" && !isUUID(filter.UserID) {
+		errs.Add("user_id", domain.ErrInvalidUserID)
+	}
+
+	if filter.ServiceName != "" {
+		if strings.TrimSpace(filter.ServiceName) == "" || strings.TrimSpace(filter.ServiceName) != filter.ServiceName {
+			errs.Add("service_name", domain.ErrInvalidServiceName)
+		}
+	}
+
+	if filter.Search != "" {
+		filter.Search = strings.TrimSpace(filter.Search)
+		if filter.Search == "" || len([]rune(filter.Search)) > domain.MaxSearchLength {
+			errs.Add("search", domain.ErrInvalidSearch)
+		}
+	}
+
+	minOK := filter.MinPrice == nil || (*filter.MinPrice >= 0 && *filter.MinPrice <= domain.MaxPrice)
+	if !minOK {
+		errs.Add("min_price", domain.ErrInvalidMinPrice)
+	}
+	maxOK := filter.MaxPrice == nil || (*filter.MaxPrice >= 0 && *filter.MaxPrice <= domain.MaxPrice)
+	if !maxOK {
+		errs.Add("max_price", domain.ErrInvalidMaxPrice)
+	}
+	if minOK && maxOK && filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		errs.Add("max_price", domain.ErrInvalidPriceRange)
+	}
+
+	if filter.ActiveIn != "" {
+		if activeIn, err := parseMonthYear(filter.ActiveIn); err != nil {
+			errs.Add("active_in", domain.ErrInvalidActiveMonth)
+		} else {
+			filter.ActiveIn = activeIn.Format(monthYearLayout)
+		}
+	}
+
+	filter.StartFrom, filter.StartTo = normalizeMonthRange(&errs, "start", filter.StartFrom, filter.StartTo, domain.ErrInvalidStartRange)
+	filter.EndFrom, filter.EndTo = normalizeMonthRange(&errs, "end", filter.EndFrom, filter.EndTo, domain.ErrInvalidEndRange)
+
+	// An open-ended subscription has no end date, so it can never match an end date range.
+	if filter.OpenEnded && (filter.EndFrom != "" || filter.EndTo != "") {
+		errs.Add("open_ended", domain.ErrInvalidEndRange)
+	}
+
+	if filter.Status != "" && !domain.IsSubscriptionStatus(filter.Status) {
+		errs.Add("status", domain.ErrInvalidStatus)
 	}
 
-	if toDate.Before(fromDate) {
-		return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidPeriod}
+	if filter.TrialEndsWithin != nil && (*filter.TrialEndsWithin < 0 || *filter.TrialEndsWithin > domain.MaxTrialWindowDays) {
+		errs.Add("trial_ends_within", domain.ErrInvalidTrialWindow)
 	}
 
-	formattedFrom := fromDate.Format(monthYearLayout)
-	formattedTo := toDate.Format(monthYearLayout)
-	filter.StartDate = formattedFrom
-	filter.EndDate = &formattedTo
+	if err := errs.Err(); err != nil {
+		return domain.ListFilter{}, err
+	}
+	return filter, nil
+}
 
-	if filter.UserID != "" {
-		if strings.TrimSpace(filter.UserID) != filter.UserID {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidUserID}
+// normalizeMonthRange validates an optional MM-YYYY range where either bound may be omitted. The
+// bounds are the prefix_from and prefix_to fields; a bad bound or a reversed range is reported
+// with err.
+func normalizeMonthRange(errs *domain.FieldErrors, prefix string, from string, to string, err error) (string, string) {
+	var fromDate, toDate time.Time
+	fromOK, toOK := true, true
+
+	if from != "" {
+		var parseErr error
+		if fromDate, parseErr = parseMonthYear(from); parseErr != nil {
+			errs.Add(prefix+"_from", err)
+			fromOK = false
+		} else {
+			from = fromDate.Format(monthYearLayout)
 		}
-		if _, err := uuid.Parse(filter.UserID); err != nil {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidUserID}
+	}
+
+	if to != "" {
+		var parseErr error
+		if toDate, parseErr = parseMonthYear(to); parseErr != nil {
+			errs.Add(prefix+"_to", err)
+			toOK = false
+		} else {
+			to = toDate.Format(monthYearLayout)
+		}
+	}
+
+	if fromOK && toOK && from != "" && to != "" && toDate.Before(fromDate) {
+		errs.Add(prefix+"_to", err)
+	}
+
+	return from, to
+}
+
+// isUUID reports whether value is a UUID without surrounding spaces.
+func isUUID(value string) bool {
+	if strings.TrimSpace(value) != value {
+		return false
+	}
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+func validateListPage(page domain.PageRequest) (domain.Pagination, error) {
+	var errs domain.FieldErrors
+
+	limit := page.Limit
+	if limit == 0 {
+		limit = domain.DefaultPageLimit
+	}
+	if limit < 0 || limit > domain.MaxPageLimit {
+		errs.Add("limit", domain.ErrInvalidLimit)
+	}
+
+	sort, err := parseSort(page.Sort)
+	if err != nil {
+		errs.Add("sort", domain.ErrInvalidSort)
+	}
+	if err := errs.Err(); err != nil {
+		return domain.Pagination{}, err
+	}
+
+	result := domain.Pagination{Limit: limit, Sort: sort}
+	if page.Cursor == "" {
+		return result, nil
+	}
+
+	cursor, err := domain.DecodeCursor(page.Cursor)
+	if err != nil || cursor.Sort != sort.String() || !isUUID(cursor.ID) || !validCursorKey(sort.Field, cursor.Key) {
+		return domain.Pagination{}, &domain.ValidationError{Err: domain.NewFieldError("cursor", domain.ErrInvalidCursor)}
+	}
+	result.After = &cursor
+
+	return result, nil
+}
+
+func parseSort(value string) (domain.Sort, error) {
+	if value == "" {
+		return domain.Sort{Field: domain.SortByCreatedAt, Desc: true}, nil
+	}
+
+	sort := domain.Sort{Field: domain.SortField(strings.TrimPrefix(value, "-")), Desc: strings.HasPrefix(value, "-")}
+	switch sort.Field {
+	case domain.SortByCreatedAt, domain.SortByPrice, domain.SortByStartDate, domain.SortByServiceName:
+		return sort, nil
+	default:
+		return domain.Sort{}, domain.ErrInvalidSort
+	}
+}
+
+// validCursorKey checks that a cursor key has the shape the repository emits for the field,
+// so a tampered cursor is rejected here instead of failing the SQL cast.
+func validCursorKey(field domain.SortField, key string) bool {
+	var err error
+	switch field {
+	case domain.SortByCreatedAt:
+		_, err = time.Parse(time.RFC3339Nano, key)
+	case domain.SortByPrice:
+		_, err = strconv.Atoi(key)
+	case domain.SortByStartDate:
+		_, err = time.Parse(dateLayout, key)
+	case domain.SortByServiceName:
+		return true
+	default:
+		return false
+	}
+	return err == nil
+}
+
+func validateTotalFilter(filter domain.TotalFilter) (domain.TotalFilter, error) {
+	var errs domain.FieldErrors
+
+	fromDate, fromOK := validatePeriodBound(&errs, "from", filter.From, domain.ErrInvalidFromDate)
+	toDate, toOK := validatePeriodBound(&errs, "to", filter.To, domain.ErrInvalidToDate)
+	if fromOK && toOK {
+		months := (toDate.Year()-fromDate.Year())*12 + int(toDate.Month()-fromDate.Month()) + 1
+		switch {
+		case toDate.Before(fromDate):
+			errs.Add("to", domain.ErrInvalidPeriod)
+		case months > domain.MaxPeriodMonths:
+			errs.Add("to", domain.ErrPeriodTooLong)
 		}
 	}
 
+	// Without a requested currency the repository picks one (see domain.TotalFilter).
+	if filter.Currency != "" && !domain.IsCurrencyCode(filter.Currency) {
+		errs.Add("currency", domain.ErrInvalidCurrency)
+	}
+
+	switch filter.Proration {
+	case "":
+		filter.Proration = domain.ProrationNone
+	case domain.ProrationNone, domain.ProrationDaily:
+	default:
+		errs.Add("proration", domain.ErrInvalidProration)
+	}
+
+	if filter.UserID != "" && !isUUID(filter.UserID) {
+		errs.Add("user_id", domain.ErrInvalidUserID)
+	}
+
 	if filter.ServiceName != "" {
 		if strings.TrimSpace(filter.ServiceName) == "" || strings.TrimSpace(filter.ServiceName) != filter.ServiceName {
-			return domain.Subscription{}, &domain.ValidationError{Err: domain.ErrInvalidServiceName}
+			errs.Add("service_name", domain.ErrInvalidServiceName)
 		}
 	}
 
+	if err := errs.Err(); err != nil {
+		return domain.TotalFilter{}, err
+	}
+
+	filter.From = fromDate.Format(monthYearLayout)
+	filter.To = toDate.Format(monthYearLayout)
 	return filter, nil
 }
 
+// validatePeriodBound parses a required MM-YYYY bound of a period, reporting a missing bound or
+// one that does not parse on field.
+func validatePeriodBound(errs *domain.FieldErrors, field string, value string, invalid error) (time.Time, bool) {
+	if strings.TrimSpace(value) == "" {
+		errs.Add(field, domain.ErrMissingRequiredFields)
+		return time.Time{}, false
+	}
+	if strings.TrimSpace(value) != value {
+		errs.Add(field, invalid)
+		return time.Time{}, false
+	}
+
+	parsed, err := parseMonthYear(value)
+	if err != nil {
+		errs.Add(field, invalid)
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+func validateGroupBy(groupBy string) (domain.TotalGroupBy, error) {
+	switch value := domain.TotalGroupBy(groupBy); value {
+	case domain.GroupByServiceName, domain.GroupByUserID, domain.GroupByMonth, domain.GroupByCurrency:
+		return value, nil
+	default:
+		return "", &domain.ValidationError{Err: domain.NewFieldError("group_by", domain.ErrInvalidGroupBy)}
+	}
+}
+
+// parseDate accepts a day (YYYY-MM-DD) or a whole month (MM-YYYY). A month resolves to its
+// first day, or to its last day when endOfMonth is set.
+func parseDate(value string, endOfMonth bool) (time.Time, error) {
+	if parsed, err := time.Parse(dateLayout, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := parseMonthYear(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfMonth {
+		return parsed.AddDate(0, 1, -1), nil
+	}
+	return parsed, nil
+}
+
 func parseMonthYear(value string) (time.Time, error) {
 	parsed, err := time.Parse(monthYearLayout, value)
 	if err != nil {
@@ -139,3 +572,26 @@ func parseMonthYear(value string) (time.Time, error) {
 	}
 	return parsed, nil
 }
+
+// validateBatch checks the batch as a whole and defaults the mode to atomic. Operations are
+// validated one by one as they are applied.
+func validateBatch(mode domain.BatchMode, ops []domain.BatchOperation) (domain.BatchMode, error) {
+	var errs domain.FieldErrors
+
+	switch mode {
+	case "":
+		mode = domain.BatchAtomic
+	case domain.BatchAtomic, domain.BatchBestEffort:
+	default:
+		errs.Add("mode", domain.ErrInvalidBatchMode)
+	}
+
+	if len(ops) == 0 || len(ops) > domain.MaxBatchSize {
+		errs.Add("operations", domain.ErrInvalidBatchSize)
+	}
+
+	if err := errs.Err(); err != nil {
+		return "", err
+	}
+	return mode, nil
+}
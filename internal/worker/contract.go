@@ -0,0 +1,15 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=contract.go -destination=mock_test.go -package=worker_test
+type trashService interface {
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type idempotencyService interface {
+	PurgeExpired(ctx context.Context) (int64, error)
+}
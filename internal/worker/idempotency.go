@@ -0,0 +1,11 @@
+package worker
+
+import (
+	"subscription_service/internal/config"
+	"subscription_service/pkg/logger"
+)
+
+// NewIdempotencyPurger removes idempotency keys that outlived their TTL.
+func NewIdempotencyPurger(log logger.Logger, service idempotencyService, cfg config.IdempotencyConfig) *Purger {
+	return newPurger(log, service.PurgeExpired, "idempotency keys", cfg.PurgeInterval)
+}
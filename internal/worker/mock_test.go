@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+//
+// Generated by this command:
+//
+//	mockgen -source=contract.go -destination=mock_test.go -package=worker_test
+//
+
+// Package worker_test is a generated GoMock package.
+package worker_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MocktrashService is a mock of trashService interface.
+type MocktrashService struct {
+	ctrl     *gomock.Controller
+	recorder *MocktrashServiceMockRecorder
+	isgomock struct{}
+}
+
+// MocktrashServiceMockRecorder is the mock recorder for MocktrashService.
+type MocktrashServiceMockRecorder struct {
+	mock *MocktrashService
+}
+
+// NewMocktrashService creates a new mock instance.
+func NewMocktrashService(ctrl *gomock.Controller) *MocktrashService {
+	mock := &MocktrashService{ctrl: ctrl}
+	mock.recorder = &MocktrashServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktrashService) EXPECT() *MocktrashServiceMockRecorder {
+	return m.recorder
+}
+
+// PurgeTrash mocks base method.
+func (m *MocktrashService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MocktrashServiceMockRecorder) PurgeTrash(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MocktrashService)(nil).PurgeTrash), ctx, retention)
+}
+
+// MockidempotencyService is a mock of idempotencyService interface.
+type MockidempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyServiceMockRecorder is the mock recorder for MockidempotencyService.
+type MockidempotencyServiceMockRecorder struct {
+	mock *MockidempotencyService
+}
+
+// NewMockidempotencyService creates a new mock instance.
+func NewMockidempotencyService(ctrl *gomock.Controller) *MockidempotencyService {
+	mock := &MockidempotencyService{ctrl: ctrl}
+	mock.recorder = &MockidempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyService) EXPECT() *MockidempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// PurgeExpired mocks base method.
+func (m *MockidempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockidempotencyServiceMockRecorder) PurgeExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockidempotencyService)(nil).PurgeExpired), ctx)
+}
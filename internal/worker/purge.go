@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"subscription_service/internal/config"
+	"subscription_service/pkg/logger"
+)
+
+// Purger periodically removes rows that outlived their retention. The label names what is
+// purged in its log lines.
+type Purger struct {
+	log      logger.Logger
+	purge    func(ctx context.Context) (int64, error)
+	label    string
+	interval time.Duration
+}
+
+func newPurger(log logger.Logger, purge func(ctx context.Context) (int64, error), label string, interval time.Duration) *Purger {
+	return &Purger{log: log, purge: purge, label: label, interval: interval}
+}
+
+// NewTrashPurger removes subscriptions that outlived the trash retention period.
+func NewTrashPurger(log logger.Logger, service trashService, cfg config.TrashConfig) *Purger {
+	purge := func(ctx context.Context) (int64, error) {
+		return service.PurgeTrash(ctx, cfg.Retention)
+	}
+	return newPurger(log, purge, "trash", cfg.PurgeInterval)
+}
+
+// Run purges once right away and then every interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce runs a single purge. Failures are logged and retried on the next tick.
+func (p *Purger) PurgeOnce(ctx context.Context) {
+	purged, err := p.purge(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.log.Error("purge "+p.label, "error", err)
+		}
+		return
+	}
+
+	if purged > 0 {
+		p.log.Info(p.label+" purged", "count", purged)
+	}
+}
@@ -0,0 +1,62 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"subscription_service/internal/config"
+	"subscription_service/internal/worker"
+	"subscription_service/pkg/logger"
+)
+
+func TestTrashPurger_PurgeOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewMocktrashService(ctrl)
+
+	cfg := config.TrashConfig{Retention: 72 * time.Hour, PurgeInterval: time.Hour}
+	svc.EXPECT().PurgeTrash(gomock.Any(), 72*time.Hour).Return(int64(3), nil)
+	svc.EXPECT().PurgeTrash(gomock.Any(), 72*time.Hour).Return(int64(0), errors.New("connection refused"))
+
+	purger := worker.NewTrashPurger(logger.NewNoop(), svc, cfg)
+	purger.PurgeOnce(context.Background())
+	purger.PurgeOnce(context.Background())
+}
+
+func TestTrashPurger_RunStopsWithContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewMocktrashService(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.EXPECT().PurgeTrash(gomock.Any(), time.Hour).DoAndReturn(func(context.Context, time.Duration) (int64, error) {
+		cancel()
+		return 0, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		worker.NewTrashPurger(logger.NewNoop(), svc, config.TrashConfig{Retention: time.Hour, PurgeInterval: time.Hour}).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after the context was cancelled")
+	}
+}
+
+func TestIdempotencyPurger_PurgeOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewMockidempotencyService(ctrl)
+
+	svc.EXPECT().PurgeExpired(gomock.Any()).Return(int64(2), nil)
+	svc.EXPECT().PurgeExpired(gomock.Any()).Return(int64(0), errors.New("connection refused"))
+
+	purger := worker.NewIdempotencyPurger(logger.NewNoop(), svc, config.IdempotencyConfig{TTL: time.Hour, PurgeInterval: time.Hour})
+	purger.PurgeOnce(context.Background())
+	purger.PurgeOnce(context.Background())
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB' CHECK (currency ~ '^[A-Z]{3}$');
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency TEXT NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency TEXT NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
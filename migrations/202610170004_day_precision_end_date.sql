@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- End dates used to be stored as the first day of the last month; they are now the last active day.
+UPDATE subscriptions
+SET end_date = (end_date + interval '1 month - 1 day')::date
+WHERE end_date IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE subscriptions
+SET end_date = date_trunc('month', end_date)::date
+WHERE end_date IS NOT NULL;
+-- +goose StatementEnd
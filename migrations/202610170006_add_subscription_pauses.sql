@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscription_pauses (
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    paused_from DATE NOT NULL,
+    resumed_from DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, paused_from),
+    CHECK (resumed_from IS NULL OR resumed_from > paused_from)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_pauses_open
+    ON subscription_pauses(subscription_id) WHERE resumed_from IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_pauses;
+-- +goose StatementEnd
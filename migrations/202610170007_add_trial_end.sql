@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS trial_end DATE;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_price_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_price_check CHECK (price > 0 OR (price = 0 AND trial_end IS NOT NULL)),
+    ADD CONSTRAINT subscriptions_trial_end_check CHECK (
+        trial_end IS NULL OR (trial_end >= start_date AND (end_date IS NULL OR trial_end <= end_date))
+    );
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_trial_end ON subscriptions(trial_end) WHERE trial_end IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscriptions_trial_end;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_trial_end_check;
+-- Free trials are real subscriptions the older schema cannot hold; they are never dropped silently.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM subscriptions WHERE price = 0) THEN
+        RAISE EXCEPTION 'cannot roll back trial_end: subscriptions with price 0 exist, give them a price first';
+    END IF;
+END;
+$$;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_price_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_price_check CHECK (price > 0);
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS trial_end;
+-- +goose StatementEnd
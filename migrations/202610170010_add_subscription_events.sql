@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscription_events (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    actor TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_events_subscription_id ON subscription_events(subscription_id, id);
+
+CREATE OR REPLACE FUNCTION reject_subscription_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_subscription_events_append_only ON subscription_events;
+CREATE TRIGGER trigger_subscription_events_append_only
+BEFORE UPDATE OR DELETE ON subscription_events
+FOR EACH STATEMENT
+EXECUTE FUNCTION reject_subscription_event_changes();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_events;
+DROP FUNCTION IF EXISTS reject_subscription_event_changes;
+-- +goose StatementEnd
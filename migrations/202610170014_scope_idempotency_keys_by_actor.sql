@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS actor TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ALTER COLUMN actor DROP DEFAULT;
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (actor, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Keys of different actors may collide once the actor is dropped; they are short-lived anyway.
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS actor;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+-- +goose StatementEnd
@@ -0,0 +1,275 @@
+// Package jwt signs and verifies compact JSON Web Tokens with HS256, RS256 or EdDSA (Ed25519).
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	ErrMalformed            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownKey           = errors.New("no key for token")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrMissingExpiry        = errors.New("token has no expiry")
+	ErrExpired              = errors.New("token expired")
+	ErrNotYetValid          = errors.New("token not yet valid")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+	ErrMissingSubject       = errors.New("token has no subject")
+)
+
+// Claims are the registered claims a token is checked against.
+type Claims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	// Scope is a space-separated list of scopes, as in OAuth 2.0.
+	Scope string   `json:"scope,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Audience is the aud claim, which may be a single string or an array.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// NumericDate is a time in seconds since the epoch.
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate truncates t to whole seconds.
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{Time: t.Truncate(time.Second)}
+}
+
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Unix())
+}
+
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	whole, frac := math.Modf(seconds)
+	d.Time = time.Unix(int64(whole), int64(frac*float64(time.Second)))
+	return nil
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// Expectations are what a Verifier requires of every token besides a valid signature.
+type Expectations struct {
+	Issuer   string
+	Audience string
+	// Leeway allows for clock skew when checking exp and nbf.
+	Leeway time.Duration
+}
+
+// Verifier checks tokens against a fixed set of keys.
+type Verifier struct {
+	keys   []Key
+	expect Expectations
+	now    func() time.Time
+}
+
+func NewVerifier(keys []Key, expect Expectations) *Verifier {
+	return &Verifier{keys: keys, expect: expect, now: time.Now}
+}
+
+// Verify checks the signature, issuer, audience, expiry and subject of token and returns its claims.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformed
+	}
+
+	var h header
+	if err := decodeJSONSegment(parts[0], &h); err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if !slices.Contains([]string{HS256, RS256, EdDSA}, h.Algorithm) {
+		return Claims{}, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return Claims{}, err
+	}
+
+	var claims Claims
+	if err := decodeJSONSegment(parts[1], &claims); err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) verifySignature(h header, signingInput string, signature []byte) error {
+	found := false
+	for _, key := range v.keys {
+		if key.Algorithm != h.Algorithm || (key.ID != "" && h.KeyID != "" && key.ID != h.KeyID) {
+			continue
+		}
+		found = true
+
+		if verify(key, []byte(signingInput), signature) {
+			return nil
+		}
+	}
+
+	if !found {
+		return ErrUnknownKey
+	}
+	return ErrInvalidSignature
+}
+
+func verify(key Key, signingInput []byte, signature []byte) bool {
+	switch k := key.key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write(signingInput)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, signingInput, signature)
+	default:
+		return false
+	}
+}
+
+func (v *Verifier) checkClaims(claims Claims) error {
+	now := v.now()
+
+	if claims.ExpiresAt == nil {
+		return ErrMissingExpiry
+	}
+	if !now.Before(claims.ExpiresAt.Add(v.expect.Leeway)) {
+		return ErrExpired
+	}
+	if claims.NotBefore != nil && now.Add(v.expect.Leeway).Before(claims.NotBefore.Time) {
+		return ErrNotYetValid
+	}
+
+	if claims.Issuer != v.expect.Issuer {
+		return ErrInvalidIssuer
+	}
+	if !slices.Contains(claims.Audience, v.expect.Audience) {
+		return ErrInvalidAudience
+	}
+
+	if claims.Subject == "" {
+		return ErrMissingSubject
+	}
+
+	return nil
+}
+
+// Sign returns a token for claims signed with key: an HMAC secret ([]byte) for HS256, an
+// *rsa.PrivateKey for RS256 or an ed25519.PrivateKey for EdDSA. keyID is left out when empty.
+func Sign(claims any, keyID string, key any) (string, error) {
+	var alg string
+	switch key.(type) {
+	case []byte:
+		alg = HS256
+	case *rsa.PrivateKey:
+		alg = RS256
+	case ed25519.PrivateKey:
+		alg = EdDSA
+	default:
+		return "", fmt.Errorf("%w: key type %T", ErrUnsupportedAlgorithm, key)
+	}
+
+	h, err := encodeJSONSegment(header{Algorithm: alg, KeyID: keyID, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeJSONSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := h + "." + payload
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			return "", fmt.Errorf("sign token: %w", err)
+		}
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signingInput))
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func encodeJSONSegment(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeJSONSegment(segment string, v any) error {
+	data, err := decodeSegment(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
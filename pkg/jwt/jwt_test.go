@@ -0,0 +1,230 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"subscription_service/pkg/jwt"
+)
+
+var (
+	hmacSecret = []byte("0123456789abcdef0123456789abcdef")
+	expect     = jwt.Expectations{Issuer: "https://auth.example.com", Audience: "subscriptions"}
+)
+
+func validClaims() jwt.Claims {
+	return jwt.Claims{
+		Issuer:    expect.Issuer,
+		Subject:   "user-1",
+		Audience:  jwt.Audience{expect.Audience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+}
+
+func sign(t *testing.T, claims any, keyID string, key any) string {
+	t.Helper()
+	token, err := jwt.Sign(claims, keyID, key)
+	require.NoError(t, err)
+	return token
+}
+
+func TestVerify_Algorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hmacKey, err := jwt.NewHMACKey("", hmacSecret)
+	require.NoError(t, err)
+
+	keys := []jwt.Key{hmacKey, jwt.NewRSAKey("rsa", &rsaKey.PublicKey), jwt.NewEd25519Key("ed", edPublic)}
+	verifier := jwt.NewVerifier(keys, expect)
+
+	for name, signingKey := range map[string]any{"HS256": hmacSecret, "RS256": rsaKey, "EdDSA": edPrivate} {
+		t.Run(name, func(t *testing.T) {
+			claims, err := verifier.Verify(sign(t, validClaims(), "", signingKey))
+			require.NoError(t, err)
+			require.Equal(t, "user-1", claims.Subject)
+		})
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	hmacKey, err := jwt.NewHMACKey("", hmacSecret)
+	require.NoError(t, err)
+	verifier := jwt.NewVerifier([]jwt.Key{hmacKey}, expect)
+
+	with := func(change func(c *jwt.Claims)) jwt.Claims {
+		claims := validClaims()
+		change(&claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{name: "garbage", token: "not.a-token", want: jwt.ErrMalformed},
+		{
+			name:  "expired",
+			token: sign(t, with(func(c *jwt.Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }), "", hmacSecret),
+			want:  jwt.ErrExpired,
+		},
+		{
+			name:  "no expiry",
+			token: sign(t, with(func(c *jwt.Claims) { c.ExpiresAt = nil }), "", hmacSecret),
+			want:  jwt.ErrMissingExpiry,
+		},
+		{
+			name:  "not yet valid",
+			token: sign(t, with(func(c *jwt.Claims) { c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) }), "", hmacSecret),
+			want:  jwt.ErrNotYetValid,
+		},
+		{
+			name:  "issuer",
+			token: sign(t, with(func(c *jwt.Claims) { c.Issuer = "https://evil.example.com" }), "", hmacSecret),
+			want:  jwt.ErrInvalidIssuer,
+		},
+		{
+			name:  "audience",
+			token: sign(t, with(func(c *jwt.Claims) { c.Audience = jwt.Audience{"billing"} }), "", hmacSecret),
+			want:  jwt.ErrInvalidAudience,
+		},
+		{
+			name:  "subject",
+			token: sign(t, with(func(c *jwt.Claims) { c.Subject = "" }), "", hmacSecret),
+			want:  jwt.ErrMissingSubject,
+		},
+		{
+			name:  "signature",
+			token: sign(t, validClaims(), "", []byte("another secret of at least 32 bytes")),
+			want:  jwt.ErrInvalidSignature,
+		},
+		{
+			name:  "none algorithm",
+			token: unsignedToken(t, validClaims()),
+			want:  jwt.ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func unsignedToken(t *testing.T, claims jwt.Claims) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+func TestVerify_AudienceString(t *testing.T) {
+	hmacKey, err := jwt.NewHMACKey("", hmacSecret)
+	require.NoError(t, err)
+	verifier := jwt.NewVerifier([]jwt.Key{hmacKey}, expect)
+
+	claims := map[string]any{
+		"iss": expect.Issuer,
+		"sub": "user-1",
+		"aud": expect.Audience,
+		"exp": float64(time.Now().Add(time.Hour).Unix()) + 0.5,
+	}
+	got, err := verifier.Verify(sign(t, claims, "", hmacSecret))
+	require.NoError(t, err)
+	require.Equal(t, jwt.Audience{expect.Audience}, got.Audience)
+}
+
+func TestVerify_KeyIsBoundToItsAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	keys, err := jwt.ParseKeyFile(publicPEM)
+	require.NoError(t, err)
+	verifier := jwt.NewVerifier(keys, expect)
+
+	// An HS256 token "signed" with the public key must not verify against it.
+	_, err = verifier.Verify(sign(t, validClaims(), "", publicPEM))
+	require.ErrorIs(t, err, jwt.ErrUnknownKey)
+
+	_, err = verifier.Verify(sign(t, validClaims(), "", rsaKey))
+	require.NoError(t, err)
+}
+
+func TestParseKeyFile(t *testing.T) {
+	keys, err := jwt.ParseKeyFile(append(hmacSecret, '\n'))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, jwt.HS256, keys[0].Algorithm)
+
+	_, err = jwt.ParseKeyFile([]byte("short"))
+	require.Error(t, err)
+
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(edPublic)
+	require.NoError(t, err)
+	keys, err = jwt.ParseKeyFile(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, jwt.EdDSA, keys[0].Algorithm)
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "use": "sig", "n": encode(rsaKey.N.Bytes()),
+			"e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": encode(edPublic)},
+		{"kty": "oct", "kid": "hmac-1", "k": encode(hmacSecret)},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": encode(rsaKey.N.Bytes()), "e": "AQAB"},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": "AA", "y": "AA"},
+	}})
+	require.NoError(t, err)
+
+	keys, err := jwt.ParseJWKS(jwks)
+	require.NoError(t, err)
+	var ids []string
+	for _, key := range keys {
+		ids = append(ids, key.ID+"/"+key.Algorithm)
+	}
+	require.Equal(t, []string{"rsa-1/RS256", "ed-1/EdDSA", "hmac-1/HS256"}, ids)
+
+	verifier := jwt.NewVerifier(keys, expect)
+	for kid, signingKey := range map[string]any{"rsa-1": rsaKey, "ed-1": edPrivate, "hmac-1": hmacSecret} {
+		_, err := verifier.Verify(sign(t, validClaims(), kid, signingKey))
+		require.NoError(t, err, kid)
+	}
+
+	// A kid that is not in the set matches no key.
+	_, err = verifier.Verify(sign(t, validClaims(), "rsa-2", rsaKey))
+	require.ErrorIs(t, err, jwt.ErrUnknownKey)
+
+	_, err = jwt.ParseJWKS([]byte(`{"keys": []}`))
+	require.Error(t, err)
+	_, err = jwt.ParseJWKS([]byte(strings.Repeat("{", 3)))
+	require.Error(t, err)
+}
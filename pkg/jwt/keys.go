@@ -0,0 +1,181 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// MinHMACSecretLength is the shortest HS256 secret accepted, 256 bits.
+const MinHMACSecretLength = 32
+
+// Key is a verification key. Its algorithm is fixed by the key itself and never taken from a
+// token, so that, for example, an RSA public key cannot be used as an HMAC secret.
+type Key struct {
+	// ID matches the kid header of a token. A key without an ID matches any token.
+	ID        string
+	Algorithm string
+	key       any // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// NewHMACKey returns an HS256 key.
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < MinHMACSecretLength {
+		return Key{}, fmt.Errorf("hmac secret must be at least %d bytes", MinHMACSecretLength)
+	}
+	return Key{ID: id, Algorithm: HS256, key: secret}, nil
+}
+
+// NewRSAKey returns an RS256 key.
+func NewRSAKey(id string, key *rsa.PublicKey) Key {
+	return Key{ID: id, Algorithm: RS256, key: key}
+}
+
+// NewEd25519Key returns an EdDSA key.
+func NewEd25519Key(id string, key ed25519.PublicKey) Key {
+	return Key{ID: id, Algorithm: EdDSA, key: key}
+}
+
+// ParseKeyFile reads the keys of a key file: one or more PEM encoded RSA or Ed25519 public keys,
+// or otherwise an HMAC secret. Surrounding whitespace of a secret is ignored.
+func ParseKeyFile(data []byte) ([]Key, error) {
+	if block, _ := pem.Decode(data); block == nil {
+		key, err := NewHMACKey("", bytes.TrimSpace(data))
+		if err != nil {
+			return nil, err
+		}
+		return []Key{key}, nil
+	}
+
+	var keys []Key
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		key, err := parsePEMBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parsePEMBlock(block *pem.Block) (Key, error) {
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("parse rsa public key: %w", err)
+		}
+		return NewRSAKey("", key), nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("parse public key: %w", err)
+		}
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			return NewRSAKey("", key), nil
+		case ed25519.PublicKey:
+			return NewEd25519Key("", key), nil
+		default:
+			return Key{}, fmt.Errorf("unsupported public key type %T", key)
+		}
+	default:
+		return Key{}, fmt.Errorf("unsupported pem block %q", block.Type)
+	}
+}
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	K         string `json:"k"`
+}
+
+// ParseJWKS reads the signature keys of a JSON Web Key Set. RSA, Ed25519 (OKP) and symmetric
+// (oct) keys are used for RS256, EdDSA and HS256; keys for other algorithms or for encryption
+// are skipped.
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	var keys []Key
+	for i, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+
+		key, ok, err := parseJWK(raw)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %d: %w", i, err)
+		}
+		if ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable signature keys")
+	}
+	return keys, nil
+}
+
+// parseJWK returns the key described by raw, or false if it is not for a supported algorithm.
+func parseJWK(raw jwk) (Key, bool, error) {
+	switch {
+	case raw.KeyType == "RSA" && (raw.Algorithm == "" || raw.Algorithm == RS256):
+		n, err := decodeSegment(raw.N)
+		if err != nil || len(n) == 0 {
+			return Key{}, false, errors.New("invalid rsa modulus")
+		}
+		e, err := decodeSegment(raw.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, false, errors.New("invalid rsa exponent")
+		}
+		exponent := new(big.Int).SetBytes(e)
+		return NewRSAKey(raw.ID, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}), true, nil
+
+	case raw.KeyType == "OKP" && raw.Curve == "Ed25519" && (raw.Algorithm == "" || raw.Algorithm == EdDSA):
+		x, err := decodeSegment(raw.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return Key{}, false, errors.New("invalid ed25519 key")
+		}
+		return NewEd25519Key(raw.ID, ed25519.PublicKey(x)), true, nil
+
+	case raw.KeyType == "oct" && (raw.Algorithm == "" || raw.Algorithm == HS256):
+		secret, err := decodeSegment(raw.K)
+		if err != nil {
+			return Key{}, false, errors.New("invalid symmetric key")
+		}
+		key, err := NewHMACKey(raw.ID, secret)
+		return key, err == nil, err
+
+	default:
+		return Key{}, false, nil
+	}
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
@@ -0,0 +1,130 @@
+// Package xlsx writes single-sheet XLSX workbooks row by row, without holding the sheet in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	rootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	sheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	sheetEnd = `</sheetData></worksheet>`
+)
+
+// Writer writes the rows of a single sheet. Close must be called to complete the file.
+type Writer struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+// NewWriter starts a workbook with one sheet named sheetName.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: contentTypes},
+		{name: "_rels/.rels", content: rootRels},
+		{name: "xl/workbook.xml", content: fmt.Sprintf(workbook, name.String())},
+		{name: "xl/_rels/workbook.xml.rels", content: workbookRels},
+	}
+	for _, part := range parts {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return nil, fmt.Errorf("write %s: %w", part.name, err)
+		}
+	}
+
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("create sheet: %w", err)
+	}
+
+	sheet := bufio.NewWriter(sw)
+	if _, err := sheet.WriteString(sheetStart); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zip: zw, sheet: sheet}, nil
+}
+
+// WriteRow appends a row. Cells may be strings, integers or nil for an empty cell.
+func (w *Writer) WriteRow(cells ...any) error {
+	for _, cell := range cells {
+		switch cell.(type) {
+		case nil, string, int, int64:
+		default:
+			return fmt.Errorf("xlsx: unsupported cell type %T", cell)
+		}
+	}
+
+	w.sheet.WriteString("<row>")
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+			w.sheet.WriteString("<c/>")
+		case string:
+			w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w.sheet, []byte(v)); err != nil {
+				return err
+			}
+			w.sheet.WriteString("</t></is></c>")
+		case int:
+			w.sheet.WriteString("<c><v>" + strconv.Itoa(v) + "</v></c>")
+		case int64:
+			w.sheet.WriteString("<c><v>" + strconv.FormatInt(v, 10) + "</v></c>")
+		}
+	}
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+// Close completes the sheet and the workbook. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if _, err := w.sheet.WriteString(sheetEnd); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
@@ -0,0 +1,58 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"subscription_service/pkg/xlsx"
+)
+
+type sheet struct {
+	Rows []struct {
+		Cells []struct {
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := xlsx.NewWriter(&buf, "Subscriptions")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow("service_name", "price", "end_date"))
+	require.NoError(t, w.WriteRow("Tom & Jerry <3", 400, nil))
+	require.NoError(t, w.WriteRow(" padded ", int64(1), "09-2025"))
+	require.Error(t, w.WriteRow(1.5))
+	require.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "_rels/.rels")
+	require.Contains(t, string(files["xl/workbook.xml"]), `name="Subscriptions"`)
+
+	var got sheet
+	require.NoError(t, xml.Unmarshal(files["xl/worksheets/sheet1.xml"], &got))
+	require.Len(t, got.Rows, 3)
+	require.Equal(t, "Tom & Jerry <3", got.Rows[1].Cells[0].Inline)
+	require.Equal(t, "inlineStr", got.Rows[1].Cells[0].Type)
+	require.Equal(t, "400", got.Rows[1].Cells[1].Value)
+	require.Empty(t, got.Rows[1].Cells[2].Value)
+	require.Equal(t, " padded ", got.Rows[2].Cells[0].Inline)
+}